@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/webhookx-io/webhookx/admin/api"
 	"github.com/webhookx-io/webhookx/config/modules"
 	"go.uber.org/zap"
 )
@@ -15,12 +16,13 @@ import (
 type Admin struct {
 	cfg *modules.AdminConfig
 	s   *http.Server
+	api *api.API
 	log *zap.SugaredLogger
 }
 
-func NewAdmin(cfg modules.AdminConfig, handler http.Handler) *Admin {
+func NewAdmin(cfg modules.AdminConfig, api *api.API) *Admin {
 	s := &http.Server{
-		Handler: handler,
+		Handler: api.Handler(),
 		Addr:    cfg.Listen,
 
 		WriteTimeout: 60 * time.Second,
@@ -30,6 +32,7 @@ func NewAdmin(cfg modules.AdminConfig, handler http.Handler) *Admin {
 	admin := &Admin{
 		cfg: &cfg,
 		s:   s,
+		api: api,
 		log: zap.S().Named("admin"),
 	}
 
@@ -72,6 +75,9 @@ func (a *Admin) Stop(ctx context.Context) error {
 	if err := a.s.Shutdown(ctx); err != nil {
 		return err
 	}
+	if err := a.api.Stop(ctx); err != nil {
+		return err
+	}
 	a.log.Infof("exit")
 	return nil
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -19,11 +20,13 @@ import (
 	"github.com/webhookx-io/webhookx/pkg/http/middlewares"
 	"github.com/webhookx-io/webhookx/pkg/http/response"
 	"github.com/webhookx-io/webhookx/pkg/openapi"
+	"github.com/webhookx-io/webhookx/pkg/secret"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
 	"github.com/webhookx-io/webhookx/pkg/tracing/instrumentations"
 	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/services"
 	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/circuitbreaker"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
@@ -34,6 +37,9 @@ type API struct {
 	declarative *declarative.Declarative
 	middlewares []mux.MiddlewareFunc
 	services    *services.Services
+	replays     *replays
+	cbm         *circuitbreaker.Manager
+	sm          *secret.SecretManager
 }
 
 type Options struct {
@@ -41,6 +47,10 @@ type Options struct {
 	DB          *db.DB
 	Dispatcher  *dispatcher.Dispatcher
 	Middlewares []mux.MiddlewareFunc
+
+	CircuitBreakerManager *circuitbreaker.Manager
+	// SecretManager resolves the secret references in endpoint TLS settings, they are rejected if nil
+	SecretManager *secret.SecretManager
 }
 
 func NewAPI(opts Options, services *services.Services) *API {
@@ -51,9 +61,17 @@ func NewAPI(opts Options, services *services.Services) *API {
 		declarative: declarative.NewDeclarative(opts.DB),
 		middlewares: opts.Middlewares,
 		services:    services,
+		replays:     newReplays(),
+		cbm:         opts.CircuitBreakerManager,
+		sm:          opts.SecretManager,
 	}
 }
 
+// Stop stops the background jobs (e.g. replaying dead letters) started by API
+func (api *API) Stop(ctx context.Context) error {
+	return api.replays.stop(ctx)
+}
+
 // param returns the value of an url variable
 func (api *API) param(r *http.Request, variable string) string {
 	return mux.Vars(r)[variable]
@@ -174,6 +192,8 @@ func (api *API) Handler() http.Handler {
 		r.HandleFunc(prefix+"/endpoints/{id}", api.GetEndpoint).Methods("GET").Name("admin.endpoints.get")
 		r.HandleFunc(prefix+"/endpoints/{id}", api.UpdateEndpoint).Methods("PUT").Name("admin.endpoints.update")
 		r.HandleFunc(prefix+"/endpoints/{id}", api.DeleteEndpoint).Methods("DELETE").Name("admin.endpoints.delete")
+		r.HandleFunc(prefix+"/endpoints/{id}/health", api.GetEndpointHealth).Methods("GET").Name("admin.endpoints.health.get")
+		r.HandleFunc(prefix+"/endpoints/{id}/health/reset", api.ResetEndpointHealth).Methods("POST").Name("admin.endpoints.health.reset")
 	}
 
 	for _, prefix := range []string{"", "/workspaces/{workspace}"} {
@@ -196,12 +216,20 @@ func (api *API) Handler() http.Handler {
 		r.HandleFunc(prefix+"/attempts/{id}", api.GetAttempt).Methods("GET").Name("admin.attempts.get")
 	}
 
+	for _, prefix := range []string{"", "/workspaces/{workspace}"} {
+		r.HandleFunc(prefix+"/dead-letters", api.PageDeadLetter).Methods("GET").Name("admin.dead_letters.page")
+		r.HandleFunc(prefix+"/dead-letters/{id}", api.GetDeadLetter).Methods("GET").Name("admin.dead_letters.get")
+		r.HandleFunc(prefix+"/dead-letters/replay", api.ReplayDeadLetters).Methods("POST").Name("admin.dead_letters.replay")
+		r.HandleFunc(prefix+"/dead-letters/replay/{id}", api.GetDeadLetterReplay).Methods("GET").Name("admin.dead_letters.replay.get")
+	}
+
 	for _, prefix := range []string{"", "/workspaces/{workspace}"} {
 		r.HandleFunc(prefix+"/plugins", api.PagePlugin).Methods("GET").Name("admin.plugins.page")
 		r.HandleFunc(prefix+"/plugins", api.CreatePlugin).Methods("POST").Name("admin.plugins.create")
 		r.HandleFunc(prefix+"/plugins/{id}", api.GetPlugin).Methods("GET").Name("admin.plugins.get")
 		r.HandleFunc(prefix+"/plugins/{id}", api.UpdatePlugin).Methods("PUT").Name("admin.plugins.update")
 		r.HandleFunc(prefix+"/plugins/{id}", api.DeletePlugin).Methods("DELETE").Name("admin.plugins.delete")
+		r.HandleFunc(prefix+"/plugins/{id}/rotate", api.RotatePlugin).Methods("POST").Name("admin.plugins.rotate")
 	}
 
 	if tracing.Enabled("request") {
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/contextx"
+	"github.com/webhookx-io/webhookx/pkg/openapi"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/services/eventbus"
+	"github.com/webhookx-io/webhookx/utils"
+	"go.uber.org/zap"
+)
+
+var (
+	errEndpointNotFound = errors.New("endpoint not found")
+	errEventNotFound    = errors.New("event not found")
+	errReplayStopped    = errors.New("replay is stopped by shutdown")
+)
+
+type TimeRange struct {
+	GTE *int64 `json:"gte"`
+	LTE *int64 `json:"lte"`
+}
+
+// DeadLetterReplayRequest is the request of replaying dead letters
+type DeadLetterReplayRequest struct {
+	EndpointId    *string    `json:"endpoint_id"`
+	EventType     *string    `json:"event_type"`
+	CreatedAt     *TimeRange `json:"created_at"`
+	BatchSize     int        `json:"batch_size"`
+	BatchInterval int64      `json:"batch_interval"`
+}
+
+func (m *DeadLetterReplayRequest) SchemaName() string {
+	return "DeadLetterReplayRequest"
+}
+
+func (m *DeadLetterReplayRequest) Query() *dao.Query {
+	var query dao.Query
+	query.Limit = m.BatchSize
+	query.Where("replayed_at", dao.Equal, nil)
+	if m.EndpointId != nil {
+		query.Where("endpoint_id", dao.Equal, *m.EndpointId)
+	}
+	if m.EventType != nil {
+		query.Where("event_type", dao.Equal, *m.EventType)
+	}
+	if m.CreatedAt != nil {
+		if m.CreatedAt.GTE != nil {
+			query.Where("created_at", dao.GreaterThanOrEqual, time.UnixMilli(*m.CreatedAt.GTE))
+		}
+		if m.CreatedAt.LTE != nil {
+			query.Where("created_at", dao.LessThanOrEqual, time.UnixMilli(*m.CreatedAt.LTE))
+		}
+	}
+	return &query
+}
+
+// replays runs the replays started by this node, they are stopped on shutdown
+type replays struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newReplays() *replays {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &replays{ctx: ctx, cancel: cancel}
+}
+
+// stop stops the running replays and waits for them to record their progress
+func (r *replays) stop(ctx context.Context) error {
+	r.cancel()
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (api *API) PageDeadLetter(w http.ResponseWriter, r *http.Request) {
+	parameters := api.lookupOperation("/workspaces/{ws_id}/dead-letters", http.MethodGet).Parameters
+	if err := openapi.ValidateParameters(r, parameters); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
+	var params DeadLetterListParams
+	if err := api.bindQuery(r, &params); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
+	query := params.Query()
+	cursor, err := api.db.DeadLettersWS.Cursor(r.Context(), query)
+	api.assert(err)
+
+	api.json(200, w, BuildPaginationResponse(cursor, r.URL))
+}
+
+func (api *API) GetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := api.param(r, "id")
+	deadLetter, err := api.db.DeadLettersWS.Get(r.Context(), id)
+	api.assert(err)
+
+	if deadLetter == nil {
+		api.json(404, w, types.ErrorResponse{Message: MsgNotFound})
+		return
+	}
+
+	api.json(200, w, deadLetter)
+}
+
+func (api *API) ReplayDeadLetters(w http.ResponseWriter, r *http.Request) {
+	var req DeadLetterReplayRequest
+	if err := ValidateRequest(r, nil, &req); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
+	query := req.Query()
+	total, err := api.db.DeadLettersWS.Count(r.Context(), query)
+	api.assert(err)
+
+	replay := &entities.DeadLetterReplay{
+		ID:        utils.KSUID(),
+		Status:    entities.DeadLetterReplayStatusRunning,
+		Total:     total,
+		StartedAt: types.NewTime(time.Now()),
+	}
+	api.assert(api.db.DeadLetterReplaysWS.Insert(r.Context(), replay))
+
+	// the replay outlives the request, but not the node
+	ctx := api.replays.ctx
+	if wctx, ok := contextx.FromContext(r.Context()); ok {
+		ctx = contextx.WithContext(ctx, wctx)
+	}
+	api.replays.wg.Go(func() {
+		api.replay(ctx, *replay, query, time.Duration(req.BatchInterval)*time.Millisecond)
+	})
+
+	api.json(202, w, replay)
+}
+
+func (api *API) GetDeadLetterReplay(w http.ResponseWriter, r *http.Request) {
+	id := api.param(r, "id")
+	replay, err := api.db.DeadLetterReplaysWS.Get(r.Context(), id)
+	api.assert(err)
+
+	if replay == nil {
+		api.json(404, w, types.ErrorResponse{Message: MsgNotFound})
+		return
+	}
+
+	api.json(200, w, replay)
+}
+
+// replay re-dispatches the dead letters matching the query in batches, the next batch starts after interval elapsed.
+// The progress is stored after each batch. The replay fails if it is stopped by shutdown.
+func (api *API) replay(ctx context.Context, replay entities.DeadLetterReplay, query *dao.Query, interval time.Duration) {
+	log := zap.S().Named("admin")
+	endpoints := make(map[string]*entities.Endpoint)
+
+	save := func() {
+		if err := api.db.DeadLetterReplaysWS.Update(context.WithoutCancel(ctx), &replay); err != nil {
+			log.Warnf("failed to save progress of replay %s: %v", replay.ID, err)
+		}
+	}
+
+	n := 0
+	iterator := api.db.DeadLettersWS.Iterate(ctx, query)
+	for iterator.Next() {
+		if n > 0 && n%query.Limit == 0 {
+			save()
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		n++
+
+		if err := api.replayDeadLetter(ctx, iterator.Current(), endpoints); err != nil {
+			log.Warnf("failed to replay dead letter %s: %v", iterator.Current().ID, err)
+			replay.Failed++
+		} else {
+			replay.Replayed++
+		}
+	}
+
+	replay.Status = entities.DeadLetterReplayStatusCompleted
+	err := iterator.Err()
+	if ctx.Err() != nil {
+		err = errReplayStopped
+	}
+	if err != nil {
+		log.Errorf("failed to replay dead letters: %v", err)
+		replay.Status = entities.DeadLetterReplayStatusFailed
+		replay.Error = new(err.Error())
+	}
+	replay.FinishedAt = new(types.NewTime(time.Now()))
+	save()
+}
+
+func (api *API) replayDeadLetter(ctx context.Context, deadLetter *entities.DeadLetter, endpoints map[string]*entities.Endpoint) error {
+	endpoint, ok := endpoints[deadLetter.EndpointId]
+	if !ok {
+		var err error
+		endpoint, err = api.db.EndpointsWS.Get(ctx, deadLetter.EndpointId)
+		if err != nil {
+			return err
+		}
+		endpoints[deadLetter.EndpointId] = endpoint
+	}
+	if endpoint == nil {
+		return errEndpointNotFound
+	}
+
+	event, err := api.db.EventsWS.Get(ctx, deadLetter.EventId)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return errEventNotFound
+	}
+
+	attempts, err := api.dispatcher.DispatchEndpoint(ctx, event, []*entities.Endpoint{endpoint})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(attempts))
+	for i, attempt := range attempts {
+		ids[i] = attempt.ID
+	}
+	err = api.services.EventBus.ClusteringBroadcast(ctx, eventbus.EventEventFanout, &eventbus.EventFanoutData{
+		EventId:    event.ID,
+		AttemptIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	return api.db.DeadLettersWS.MarkReplayed(ctx, deadLetter.ID)
+}
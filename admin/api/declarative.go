@@ -70,7 +70,7 @@ func (api *API) Sync(w http.ResponseWriter, r *http.Request) {
 
 	cfg.Init()
 
-	if err := cfg.Validate(); err != nil {
+	if err := cfg.Validate(endpointValidators...); err != nil {
 		api.error(400, w, err)
 		return
 	}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/worker/circuitbreaker"
+)
+
+// EndpointHealth is the circuit breaker state and metrics of an endpoint
+type EndpointHealth struct {
+	Enabled     bool                              `json:"enabled"`
+	State       circuitbreaker.State              `json:"state"`
+	FailureRate float64                           `json:"failure_rate"`
+	Metric      circuitbreaker.TimeBucketMetric   `json:"metric"`
+	Buckets     []circuitbreaker.TimeBucketMetric `json:"buckets"`
+	OpenedAt    *types.Time                       `json:"opened_at"`
+	Reason      *string                           `json:"reason"`
+	ResetAt     *types.Time                       `json:"reset_at"`
+}
+
+func optionalTime(t time.Time) *types.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return new(types.NewTime(t))
+}
+
+func (api *API) buildEndpointHealth(ctx context.Context, endpoint *entities.Endpoint) *EndpointHealth {
+	thresholds, _ := circuitbreaker.EndpointThresholds(endpoint)
+	cb, err := api.cbm.GetCircuitBreaker(ctx, endpoint.ID, thresholds)
+	api.assert(err)
+
+	health := &EndpointHealth{
+		Enabled:     endpoint.Enabled,
+		State:       cb.State(),
+		FailureRate: cb.Metric().FailureRate(),
+		Metric:      cb.Metric(),
+		Buckets:     cb.Buckets(),
+		OpenedAt:    optionalTime(cb.OpenedAt()),
+		ResetAt:     optionalTime(cb.ResetAt()),
+	}
+	if cb.Reason() != "" {
+		health.Reason = new(cb.Reason())
+	}
+	return health
+}
+
+func (api *API) GetEndpointHealth(w http.ResponseWriter, r *http.Request) {
+	id := api.param(r, "id")
+	endpoint, err := api.db.EndpointsWS.Get(r.Context(), id)
+	api.assert(err)
+	if endpoint == nil {
+		api.json(404, w, types.ErrorResponse{Message: MsgNotFound})
+		return
+	}
+
+	api.json(200, w, api.buildEndpointHealth(r.Context(), endpoint))
+}
+
+func (api *API) ResetEndpointHealth(w http.ResponseWriter, r *http.Request) {
+	id := api.param(r, "id")
+	endpoint, err := api.db.EndpointsWS.Get(r.Context(), id)
+	api.assert(err)
+	if endpoint == nil {
+		api.json(404, w, types.ErrorResponse{Message: MsgNotFound})
+		return
+	}
+
+	api.assert(api.cbm.Reset(r.Context(), endpoint.ID))
+
+	api.json(200, w, api.buildEndpointHealth(r.Context(), endpoint))
+}
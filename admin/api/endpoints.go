@@ -1,15 +1,33 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/pkg/contextx"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/filter"
 	"github.com/webhookx-io/webhookx/pkg/openapi"
+	"github.com/webhookx-io/webhookx/pkg/secret/reference"
 	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/deliverer"
 )
 
+// endpointValidators validates the fields of endpoint that entities does not validate
+var endpointValidators = []entities.EndpointValidator{validateFilter, deliverer.Validate}
+
+func validateFilter(m *entities.Endpoint) map[string]interface{} {
+	if m.Filter == nil {
+		return nil
+	}
+	if _, err := filter.Compile(*m.Filter); err != nil {
+		return map[string]interface{}{"filter": err.Error()}
+	}
+	return nil
+}
+
 func (api *API) PageEndpoint(w http.ResponseWriter, r *http.Request) {
 	parameters := api.lookupOperation("/workspaces/{ws_id}/endpoints", http.MethodGet).Parameters
 	if err := openapi.ValidateParameters(r, parameters); err != nil {
@@ -51,6 +69,17 @@ func (api *API) CreateEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	endpoint.Init()
+	if err := endpoint.Validate(endpointValidators...); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
+	if err := api.validateTLSReferences(r.Context(), endpoint.Request.TLS); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
 	endpoint.WorkspaceId = contextx.GetWorkspaceID(r.Context())
 	err := api.db.EndpointsWS.Insert(r.Context(), &endpoint)
 	api.assert(err)
@@ -73,6 +102,17 @@ func (api *API) UpdateEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	endpoint.Init()
+	if err := endpoint.Validate(endpointValidators...); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
+	if err := api.validateTLSReferences(r.Context(), endpoint.Request.TLS); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
 	endpoint.ID = id
 	err = api.db.EndpointsWS.Update(r.Context(), endpoint)
 	api.assert(err)
@@ -80,6 +120,44 @@ func (api *API) UpdateEndpoint(w http.ResponseWriter, r *http.Request) {
 	api.json(200, w, endpoint)
 }
 
+// validateTLSReferences validates that the secret references in TLS settings can be resolved,
+// otherwise the deliveries would fail until the endpoint is fixed.
+func (api *API) validateTLSReferences(ctx context.Context, tls *entities.TLS) error {
+	if tls == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{})
+	for name, value := range map[string]*string{
+		"client_cert": tls.ClientCert,
+		"client_key":  tls.ClientKey,
+		"ca_cert":     tls.CACert,
+	} {
+		if value == nil || !reference.IsReference(*value) {
+			continue
+		}
+		if api.sm == nil {
+			fields[name] = "secret reference is not supported as secret manager is not enabled"
+			continue
+		}
+		ref, err := reference.Parse(*value)
+		if err != nil {
+			fields[name] = err.Error()
+			continue
+		}
+		if _, err := api.sm.ResolveReference(ctx, ref); err != nil {
+			fields[name] = err.Error()
+		}
+	}
+
+	if len(fields) > 0 {
+		e := errs.NewValidateError(errs.ErrRequestValidation)
+		e.Fields["request"] = map[string]interface{}{"tls": fields}
+		return e
+	}
+	return nil
+}
+
 func (api *API) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
 	id := api.param(r, "id")
 	_, err := api.db.EndpointsWS.Delete(r.Context(), id)
@@ -395,3 +395,50 @@ func (p *EventListParams) Query() *dao.Query {
 	}
 	return query
 }
+
+type DeadLetterListParams struct {
+	ListParams
+
+	CreatedAt    *int64  `form:"created_at"`
+	CreatedAtGT  *int64  `form:"created_at[gt]"`
+	CreatedAtGTE *int64  `form:"created_at[gte]"`
+	CreatedAtLT  *int64  `form:"created_at[lt]"`
+	CreatedAtLTE *int64  `form:"created_at[lte]"`
+	EventId      *string `form:"event_id"`
+	EventType    *string `form:"event_type"`
+	EndpointId   *string `form:"endpoint_id"`
+	Reason       *string `form:"reason"`
+}
+
+func (p *DeadLetterListParams) Query() *dao.Query {
+	query := p.ListParams.Query()
+
+	if p.CreatedAt != nil {
+		query.Where("created_at", dao.Equal, time.UnixMilli(*p.CreatedAt))
+	}
+	if p.CreatedAtGT != nil {
+		query.Where("created_at", dao.GreaterThan, time.UnixMilli(*p.CreatedAtGT))
+	}
+	if p.CreatedAtGTE != nil {
+		query.Where("created_at", dao.GreaterThanOrEqual, time.UnixMilli(*p.CreatedAtGTE))
+	}
+	if p.CreatedAtLT != nil {
+		query.Where("created_at", dao.LessThan, time.UnixMilli(*p.CreatedAtLT))
+	}
+	if p.CreatedAtLTE != nil {
+		query.Where("created_at", dao.LessThanOrEqual, time.UnixMilli(*p.CreatedAtLTE))
+	}
+	if p.EventId != nil {
+		query.Where("event_id", dao.Equal, *p.EventId)
+	}
+	if p.EventType != nil {
+		query.Where("event_type", dao.Equal, *p.EventType)
+	}
+	if p.EndpointId != nil {
+		query.Where("endpoint_id", dao.Equal, *p.EndpointId)
+	}
+	if p.Reason != nil {
+		query.Where("reason", dao.Equal, *p.Reason)
+	}
+	return query
+}
@@ -3,10 +3,13 @@ package api
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/pkg/errs"
 	"github.com/webhookx-io/webhookx/pkg/openapi"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/utils"
 )
@@ -100,6 +103,49 @@ func (api *API) UpdatePlugin(w http.ResponseWriter, r *http.Request) {
 	api.json(200, w, model)
 }
 
+// RotatePlugin generates a new secret for the plugin, the current secret remains active during the overlap
+func (api *API) RotatePlugin(w http.ResponseWriter, r *http.Request) {
+	parameters := api.lookupOperation("/workspaces/{ws_id}/plugins/{id}/rotate", http.MethodPost).Parameters
+	if err := openapi.ValidateParameters(r, parameters); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
+	overlap := int64(86400)
+	if v := r.URL.Query().Get("overlap"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			api.json(400, w, types.ErrorResponse{Message: "invalid overlap: " + err.Error()})
+			return
+		}
+		overlap = n
+	}
+
+	id := api.param(r, "id")
+	model, err := api.db.PluginsWS.Get(r.Context(), id)
+	api.assert(err)
+	if model == nil {
+		api.json(404, w, types.ErrorResponse{Message: MsgNotFound})
+		return
+	}
+
+	p, err := model.ToPlugin()
+	api.assert(err)
+	rotator, ok := p.(plugin.SecretRotator)
+	if !ok {
+		api.json(400, w, types.ErrorResponse{Message: "plugin '" + model.Name + "' does not support secret rotation"})
+		return
+	}
+	api.assert(p.Init(model.Config))
+	rotator.RotateSecret(time.Now(), time.Duration(overlap)*time.Second)
+	model.Config = p.GetConfig()
+
+	err = api.db.PluginsWS.Update(r.Context(), model)
+	api.assert(err)
+
+	api.json(200, w, model)
+}
+
 func (api *API) DeletePlugin(w http.ResponseWriter, r *http.Request) {
 	id := api.param(r, "id")
 	_, err := api.db.PluginsWS.Delete(r.Context(), id)
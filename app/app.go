@@ -33,6 +33,7 @@ import (
 	"github.com/webhookx-io/webhookx/pkg/ratelimiter"
 	"github.com/webhookx-io/webhookx/pkg/reports"
 	"github.com/webhookx-io/webhookx/pkg/secret"
+	"github.com/webhookx-io/webhookx/pkg/semaphore"
 	"github.com/webhookx-io/webhookx/pkg/stats"
 	"github.com/webhookx-io/webhookx/pkg/store"
 	"github.com/webhookx-io/webhookx/pkg/taskqueue"
@@ -53,6 +54,7 @@ import (
 	"github.com/webhookx-io/webhookx/worker"
 	"github.com/webhookx-io/webhookx/worker/circuitbreaker"
 	"github.com/webhookx-io/webhookx/worker/deliverer"
+	"github.com/webhookx-io/webhookx/worker/ordering"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/zap"
 )
@@ -148,10 +150,11 @@ func (app *Application) initialize() error {
 	stats.Register(db)
 
 	dispatcher := dispatcher.NewDispatcher(dispatcher.Options{
-		DB:       db,
-		Metrics:  metrics,
-		Registry: dispatcher.NewRegistry(db),
-		EventBus: eventBus,
+		DB:        db,
+		Metrics:   metrics,
+		Registry:  dispatcher.NewRegistry(db),
+		EventBus:  eventBus,
+		Sequencer: ordering.NewSequencer(client),
 	})
 
 	// shared services
@@ -160,6 +163,7 @@ func (app *Application) initialize() error {
 		EventBus:    eventBus,
 		Metrics:     metrics,
 		RateLimiter: ratelimiter.NewRedisLimiter(client),
+		Semaphore:   semaphore.NewRedisSemaphore(client),
 		Distributed: distributed.NewDistributed(
 			distributed.WithLogger(app.log.Named("distributed")),
 			distributed.WithLocker(distributed.NewRedisLocker(client)),
@@ -176,13 +180,20 @@ func (app *Application) initialize() error {
 		services.Task = task.NewTaskService(app.log, db, queue)
 	}
 
+	if err := app.initSecretMananger(&cfg.Secret); err != nil {
+		return err
+	}
+
+	// circuit breaker state is shared by worker and admin
+	cbm := newCircuitBreakerManager(&cfg.Worker.CircuitBreaker, client)
+
 	// worker
-	if err := app.initWorker(&cfg.Worker, services, client); err != nil {
+	if err := app.initWorker(&cfg.Worker, services, dispatcher, client, cbm); err != nil {
 		return err
 	}
 
 	// admin
-	if err := app.initAdmin(&cfg.Admin, services, dispatcher); err != nil {
+	if err := app.initAdmin(&cfg.Admin, services, dispatcher, cbm); err != nil {
 		return err
 	}
 
@@ -196,10 +207,6 @@ func (app *Application) initialize() error {
 		return err
 	}
 
-	if err := app.initSecretMananger(&cfg.Secret); err != nil {
-		return err
-	}
-
 	if err := app.initRetention(&cfg.Retention, services); err != nil {
 		return err
 	}
@@ -235,11 +242,23 @@ func (app *Application) initCache(cfg *config.Config, client *redis.Client) erro
 	return nil
 }
 
-func (app *Application) initWorker(cfg *modules.WorkerConfig, services *services.Services, client *redis.Client) error {
+func newCircuitBreakerManager(cfg *modules.CircuitBreaker, client *redis.Client) *circuitbreaker.Manager {
+	return circuitbreaker.NewManager(
+		circuitbreaker.WithTimeWindowSize(cfg.WindowSize),
+		circuitbreaker.WithFailureRateThreshold(cfg.FailureRateThreshold),
+		circuitbreaker.WithMinimumRequestThreshold(cfg.MinimumRequestThreshold),
+		circuitbreaker.WithCooldown(cfg.Cooldown),
+		circuitbreaker.WithHalfOpenProbes(cfg.HalfOpenProbes),
+		circuitbreaker.WithRedisClient(client),
+		circuitbreaker.WithEnabled(cfg.Enabled))
+}
+
+func (app *Application) initWorker(cfg *modules.WorkerConfig, services *services.Services, d *dispatcher.Dispatcher, client *redis.Client, cbm *circuitbreaker.Manager) error {
 	if cfg.Enabled {
 		delivererOptions := deliverer.Options{
-			Logger:         app.log.Named("deliverer"),
-			RequestTimeout: time.Duration(cfg.Deliverer.Timeout) * time.Millisecond,
+			Logger:              app.log.Named("deliverer"),
+			RequestTimeout:      time.Duration(cfg.Deliverer.Timeout) * time.Millisecond,
+			MaxResponseBodySize: cfg.Deliverer.MaxResponseBodySize,
 		}
 		if cfg.Deliverer.Proxy != "" {
 			delivererOptions.ProxyOptions = &deliverer.ProxyOptions{
@@ -256,31 +275,34 @@ func (app *Application) initWorker(cfg *modules.WorkerConfig, services *services
 			}
 		}
 
-		worker := worker.NewWorker(worker.Options{
-			PoolSize:         int(cfg.Pool.Size),
-			PoolConcurrency:  int(cfg.Pool.Concurrency),
-			DelivererOptions: delivererOptions,
-			DB:               app.db,
-			RedisClient:      client,
-			CircuitBreakerManager: circuitbreaker.NewManager(
-				circuitbreaker.WithTimeWindowSize(cfg.CircuitBreaker.WindowSize),
-				circuitbreaker.WithFailureRateThreshold(cfg.CircuitBreaker.FailureRateThreshold),
-				circuitbreaker.WithMinimumRequestThreshold(cfg.CircuitBreaker.MinimumRequestThreshold),
-				circuitbreaker.WithRedisClient(client),
-				circuitbreaker.WithEnabled(cfg.CircuitBreaker.Enabled)),
-			EnabledDetection: cfg.CircuitBreaker.Enabled,
-		}, services)
+		opts := worker.Options{
+			PoolSize:              int(cfg.Pool.Size),
+			PoolConcurrency:       int(cfg.Pool.Concurrency),
+			DelivererOptions:      delivererOptions,
+			DB:                    app.db,
+			RedisClient:           client,
+			CircuitBreakerManager: cbm,
+			EnabledDetection:      cfg.CircuitBreaker.Enabled,
+			Dispatcher:            d,
+			SecretManager:         app.sm,
+		}
+		if cfg.SystemEvents.Enabled {
+			opts.SystemEventsWorkspace = cfg.SystemEvents.Workspace
+		}
+		worker := worker.NewWorker(opts, services)
 		app.registerService(worker)
 	}
 	return nil
 }
 
-func (app *Application) initAdmin(cfg *modules.AdminConfig, services *services.Services, d *dispatcher.Dispatcher) error {
+func (app *Application) initAdmin(cfg *modules.AdminConfig, services *services.Services, d *dispatcher.Dispatcher, cbm *circuitbreaker.Manager) error {
 	if cfg.IsEnabled() {
 		opts := api.Options{
-			Config:     app.cfg,
-			DB:         app.db,
-			Dispatcher: d,
+			Config:                app.cfg,
+			DB:                    app.db,
+			Dispatcher:            d,
+			CircuitBreakerManager: cbm,
+			SecretManager:         app.sm,
 		}
 		if app.cfg.AccessLog.Enabled {
 			accessLogger, err := accesslog.NewAccessLogger("admin", accesslog.Options{
@@ -293,7 +315,7 @@ func (app *Application) initAdmin(cfg *modules.AdminConfig, services *services.S
 			}
 			opts.Middlewares = append(opts.Middlewares, accesslog.NewMiddleware(accessLogger))
 		}
-		admin := admin.NewAdmin(*cfg, api.NewAPI(opts, services).Handler())
+		admin := admin.NewAdmin(*cfg, api.NewAPI(opts, services))
 		app.registerService(admin)
 	}
 	return nil
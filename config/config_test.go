@@ -391,10 +391,29 @@ func TestWorkerConfig(t *testing.T) {
 					WindowSize:              3600,
 					FailureRateThreshold:    80,
 					MinimumRequestThreshold: 100,
+					Cooldown:                60,
+					HalfOpenProbes:          3,
 				},
 			},
 			validateErr: nil,
 		},
+		{
+			desc: "invalid system_events: empty workspace",
+			cfg: modules.WorkerConfig{
+				CircuitBreaker: modules.CircuitBreaker{
+					WindowSize:              3600,
+					FailureRateThreshold:    80,
+					MinimumRequestThreshold: 100,
+					Cooldown:                60,
+					HalfOpenProbes:          3,
+				},
+				SystemEvents: modules.SystemEvents{
+					Enabled:   true,
+					Workspace: "",
+				},
+			},
+			validateErr: errors.New("system_events.workspace is required"),
+		},
 		{
 			desc: "invalid deliverer configuration: negative timeout",
 			cfg: modules.WorkerConfig{
@@ -405,6 +424,15 @@ func TestWorkerConfig(t *testing.T) {
 			},
 			validateErr: errors.New("deliverer.timeout cannot be negative"),
 		},
+		{
+			desc: "invalid deliverer configuration: negative max_response_body_size",
+			cfg: modules.WorkerConfig{
+				Deliverer: modules.WorkerDeliverer{
+					MaxResponseBodySize: -1,
+				},
+			},
+			validateErr: errors.New("deliverer.max_response_body_size cannot be negative"),
+		},
 		{
 			desc: "invalid deliverer configuration: invalid acl configuration 1",
 			cfg: modules.WorkerConfig{
@@ -508,6 +536,8 @@ func TestCircuitBreaker(t *testing.T) {
 				WindowSize:              3600,
 				FailureRateThreshold:    80,
 				MinimumRequestThreshold: 100,
+				Cooldown:                60,
+				HalfOpenProbes:          3,
 			},
 			expectedValidateErr: nil,
 		},
@@ -517,6 +547,8 @@ func TestCircuitBreaker(t *testing.T) {
 				WindowSize:              0,
 				FailureRateThreshold:    80,
 				MinimumRequestThreshold: 100,
+				Cooldown:                60,
+				HalfOpenProbes:          3,
 			},
 			expectedValidateErr: errors.New("window_size must be in the range [60, 86400]"),
 		},
@@ -526,6 +558,8 @@ func TestCircuitBreaker(t *testing.T) {
 				WindowSize:              86401,
 				FailureRateThreshold:    80,
 				MinimumRequestThreshold: 100,
+				Cooldown:                60,
+				HalfOpenProbes:          3,
 			},
 			expectedValidateErr: errors.New("window_size must be in the range [60, 86400]"),
 		},
@@ -535,6 +569,8 @@ func TestCircuitBreaker(t *testing.T) {
 				WindowSize:              3600,
 				FailureRateThreshold:    0,
 				MinimumRequestThreshold: 100,
+				Cooldown:                60,
+				HalfOpenProbes:          3,
 			},
 			expectedValidateErr: errors.New("failure_rate_threshold must be in the range [1, 100]"),
 		},
@@ -544,6 +580,8 @@ func TestCircuitBreaker(t *testing.T) {
 				WindowSize:              3600,
 				FailureRateThreshold:    101,
 				MinimumRequestThreshold: 100,
+				Cooldown:                60,
+				HalfOpenProbes:          3,
 			},
 			expectedValidateErr: errors.New("failure_rate_threshold must be in the range [1, 100]"),
 		},
@@ -553,9 +591,33 @@ func TestCircuitBreaker(t *testing.T) {
 				WindowSize:              3600,
 				FailureRateThreshold:    80,
 				MinimumRequestThreshold: 0,
+				Cooldown:                60,
+				HalfOpenProbes:          3,
 			},
 			expectedValidateErr: errors.New("minimum_request_threshold must be greater than 1"),
 		},
+		{
+			desc: "invalid cooldown: 0",
+			cfg: modules.CircuitBreaker{
+				WindowSize:              3600,
+				FailureRateThreshold:    80,
+				MinimumRequestThreshold: 100,
+				Cooldown:                0,
+				HalfOpenProbes:          3,
+			},
+			expectedValidateErr: errors.New("cooldown must be greater than 1"),
+		},
+		{
+			desc: "invalid half_open_probes: 0",
+			cfg: modules.CircuitBreaker{
+				WindowSize:              3600,
+				FailureRateThreshold:    80,
+				MinimumRequestThreshold: 100,
+				Cooldown:                60,
+				HalfOpenProbes:          0,
+			},
+			expectedValidateErr: errors.New("half_open_probes must be greater than 1"),
+		},
 	}
 	for _, test := range tests {
 		actualValidateErr := test.cfg.Validate()
@@ -750,6 +812,23 @@ func TestLoadRetentionConfigFromEnvironment(t *testing.T) {
 	assert.Equal(t, configtypes.Duration(60*24*time.Hour), cfg.Retention.TTL.Attempts)
 }
 
+func TestLoadCircuitBreakerConfigFromEnvironment(t *testing.T) {
+	cfg := New()
+	err := NewLoader(cfg).
+		WithEnvPrefix("WEBHOOKX").
+		WithEnv(map[string]string{
+			"WEBHOOKX_WORKER_CIRCUITBREAKER_ENABLED":          "true",
+			"WEBHOOKX_WORKER_CIRCUITBREAKER_COOLDOWN":         "5",
+			"WEBHOOKX_WORKER_CIRCUITBREAKER_HALF_OPEN_PROBES": "2",
+		}).
+		Load()
+
+	assert.NoError(t, err)
+	assert.True(t, cfg.Worker.CircuitBreaker.Enabled)
+	assert.Equal(t, 5, cfg.Worker.CircuitBreaker.Cooldown)
+	assert.Equal(t, 2, cfg.Worker.CircuitBreaker.HalfOpenProbes)
+}
+
 func TestLoadRetentionConfigRejectsInvalidTTL(t *testing.T) {
 	cfg := New()
 	err := NewLoader(cfg).
@@ -10,19 +10,23 @@ import (
 )
 
 type WorkerDeliverer struct {
-	Timeout        int64     `yaml:"timeout" json:"timeout" default:"60000"`
-	ACL            ACLConfig `yaml:"acl" json:"acl"`
-	Proxy          string    `yaml:"proxy" json:"proxy"`
-	ProxyTLSCert   string    `yaml:"proxy_tls_cert" json:"proxy_tls_cert" envconfig:"PROXY_TLS_CERT"`
-	ProxyTLSKey    string    `yaml:"proxy_tls_key" json:"proxy_tls_key" envconfig:"PROXY_TLS_KEY"`
-	ProxyTLSCaCert string    `yaml:"proxy_tls_ca_cert" json:"proxy_tls_ca_cert" envconfig:"PROXY_TLS_CA_CERT"`
-	ProxyTLSVerify bool      `yaml:"proxy_tls_verify" json:"proxy_tls_verify" envconfig:"PROXY_TLS_VERIFY"`
+	Timeout             int64     `yaml:"timeout" json:"timeout" default:"60000"`
+	MaxResponseBodySize int64     `yaml:"max_response_body_size" json:"max_response_body_size" default:"1048576" envconfig:"MAX_RESPONSE_BODY_SIZE"`
+	ACL                 ACLConfig `yaml:"acl" json:"acl"`
+	Proxy               string    `yaml:"proxy" json:"proxy"`
+	ProxyTLSCert        string    `yaml:"proxy_tls_cert" json:"proxy_tls_cert" envconfig:"PROXY_TLS_CERT"`
+	ProxyTLSKey         string    `yaml:"proxy_tls_key" json:"proxy_tls_key" envconfig:"PROXY_TLS_KEY"`
+	ProxyTLSCaCert      string    `yaml:"proxy_tls_ca_cert" json:"proxy_tls_ca_cert" envconfig:"PROXY_TLS_CA_CERT"`
+	ProxyTLSVerify      bool      `yaml:"proxy_tls_verify" json:"proxy_tls_verify" envconfig:"PROXY_TLS_VERIFY"`
 }
 
 func (cfg *WorkerDeliverer) Validate() error {
 	if cfg.Timeout < 0 {
 		return fmt.Errorf("deliverer.timeout cannot be negative")
 	}
+	if cfg.MaxResponseBodySize < 0 {
+		return fmt.Errorf("deliverer.max_response_body_size cannot be negative")
+	}
 	if err := cfg.ACL.Validate(); err != nil {
 		return err
 	}
@@ -53,6 +57,7 @@ type WorkerConfig struct {
 	Deliverer      WorkerDeliverer `yaml:"deliverer" json:"deliverer"`
 	Pool           Pool            `yaml:"pool" json:"pool"`
 	CircuitBreaker CircuitBreaker  `yaml:"circuitbreaker" json:"circuitbreaker"`
+	SystemEvents   SystemEvents    `yaml:"system_events" json:"system_events" envconfig:"SYSTEM_EVENTS"`
 }
 
 func (cfg *WorkerConfig) Status() string {
@@ -100,6 +105,9 @@ func (cfg *WorkerConfig) Validate() error {
 	if err := cfg.CircuitBreaker.Validate(); err != nil {
 		return err
 	}
+	if err := cfg.SystemEvents.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -109,6 +117,8 @@ type CircuitBreaker struct {
 	WindowSize              int  `yaml:"window_size" json:"window_size" default:"3600" envconfig:"WINDOW_SIZE"`
 	FailureRateThreshold    int  `yaml:"failure_rate_threshold" json:"failure_rate_threshold" default:"80" envconfig:"FAILURE_RATE_THRESHOLD"`
 	MinimumRequestThreshold int  `yaml:"minimum_request_threshold" json:"minimum_request_threshold" default:"100" envconfig:"MINIMUM_REQUEST_THRESHOLD"`
+	Cooldown                int  `yaml:"cooldown" json:"cooldown" default:"60" envconfig:"COOLDOWN"`
+	HalfOpenProbes          int  `yaml:"half_open_probes" json:"half_open_probes" default:"3" envconfig:"HALF_OPEN_PROBES"`
 }
 
 func (cfg CircuitBreaker) Validate() error {
@@ -121,5 +131,24 @@ func (cfg CircuitBreaker) Validate() error {
 	if cfg.MinimumRequestThreshold < 1 {
 		return errors.New("minimum_request_threshold must be greater than 1")
 	}
+	if cfg.Cooldown < 1 {
+		return errors.New("cooldown must be greater than 1")
+	}
+	if cfg.HalfOpenProbes < 1 {
+		return errors.New("half_open_probes must be greater than 1")
+	}
+	return nil
+}
+
+type SystemEvents struct {
+	BaseConfig
+	Enabled   bool   `yaml:"enabled" json:"enabled" default:"false"`
+	Workspace string `yaml:"workspace" json:"workspace" default:"default"`
+}
+
+func (cfg SystemEvents) Validate() error {
+	if cfg.Enabled && cfg.Workspace == "" {
+		return errors.New("system_events.workspace is required")
+	}
 	return nil
 }
@@ -24,14 +24,16 @@ func (c CacheKey) Build(id string, suffixes ...string) string {
 }
 
 var (
-	EventCacheKey         = register(CacheKey{"events", "v1"})
-	EndpointCacheKey      = register(CacheKey{"endpoints", "v1"})
-	SourceCacheKey        = register(CacheKey{"sources", "v1"})
-	WorkspaceCacheKey     = register(CacheKey{"workspaces", "v1"})
-	AttemptCacheKey       = register(CacheKey{"attempts", "v1"})
-	PluginCacheKey        = register(CacheKey{"plugins", "v1"})
-	AttemptDetailCacheKey = register(CacheKey{"attempt_details", "v1"})
-	WorkspaceEndpointsKey = register(CacheKey{"workspaces_endpoints", "v1"})
+	EventCacheKey            = register(CacheKey{"events", "v1"})
+	EndpointCacheKey         = register(CacheKey{"endpoints", "v1"})
+	SourceCacheKey           = register(CacheKey{"sources", "v1"})
+	WorkspaceCacheKey        = register(CacheKey{"workspaces", "v1"})
+	AttemptCacheKey          = register(CacheKey{"attempts", "v1"})
+	PluginCacheKey           = register(CacheKey{"plugins", "v1"})
+	AttemptDetailCacheKey    = register(CacheKey{"attempt_details", "v1"})
+	WorkspaceEndpointsKey    = register(CacheKey{"workspaces_endpoints", "v1"})
+	DeadLetterCacheKey       = register(CacheKey{"dead_letters", "v1"})
+	DeadLetterReplayCacheKey = register(CacheKey{"dead_letter_replays", "v1"})
 )
 
 var registry = map[string]CacheKey{}
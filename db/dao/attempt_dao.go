@@ -17,13 +17,15 @@ type attemptDao struct {
 }
 
 type AttemptResult struct {
-	ID          string
-	Request     *entities.AttemptRequest
-	Response    *entities.AttemptResponse
-	AttemptedAt types.Time
-	Status      entities.AttemptStatus
-	ErrorCode   *entities.AttemptErrorCode
-	Exhausted   bool
+	ID              string
+	Request         *entities.AttemptRequest
+	Response        *entities.AttemptResponse
+	AttemptedAt     types.Time
+	Status          entities.AttemptStatus
+	ErrorCode       *entities.AttemptErrorCode
+	Exhausted       bool
+	ExhaustedReason *entities.AttemptExhaustedReason
+	RetryDecision   *entities.AttemptRetryDecision
 }
 
 func NewAttemptDao(db *sqlx.DB, fns ...OptionFunc) AttemptDAO {
@@ -44,13 +46,15 @@ func (dao *attemptDao) UpdateDelivery(ctx context.Context, result *AttemptResult
 	defer span.End()
 
 	_, err := dao.executeUpdate(ctx, map[string]interface{}{
-		"request":      result.Request,
-		"response":     result.Response,
-		"attempted_at": result.AttemptedAt,
-		"status":       result.Status,
-		"error_code":   result.ErrorCode,
-		"exhausted":    result.Exhausted,
-		"updated_at":   sq.Expr("NOW()"),
+		"request":          result.Request,
+		"response":         result.Response,
+		"attempted_at":     result.AttemptedAt,
+		"status":           result.Status,
+		"error_code":       result.ErrorCode,
+		"exhausted":        result.Exhausted,
+		"exhausted_reason": result.ExhaustedReason,
+		"retry_decision":   result.RetryDecision,
+		"updated_at":       sq.Expr("NOW()"),
 	}, map[string]interface{}{
 		"id": result.ID,
 	})
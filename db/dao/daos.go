@@ -57,6 +57,15 @@ type AttemptDetailDAO interface {
 	Insert(ctx context.Context, attemptDetail *entities.AttemptDetail) error
 }
 
+type DeadLetterDAO interface {
+	BaseDAO[entities.DeadLetter]
+	MarkReplayed(ctx context.Context, id string) error
+}
+
+type DeadLetterReplayDAO interface {
+	BaseDAO[entities.DeadLetterReplay]
+}
+
 type PluginDAO interface {
 	BaseDAO[entities.Plugin]
 }
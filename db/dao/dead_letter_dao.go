@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+type deadLetterDao struct {
+	*DAO[entities.DeadLetter]
+}
+
+func NewDeadLetterDao(db *sqlx.DB, fns ...OptionFunc) DeadLetterDAO {
+	opts := Options{
+		Table:          "dead_letters",
+		EntityName:     "dead_letter",
+		CachePropagate: false,
+		CacheName:      constants.DeadLetterCacheKey.Name,
+	}
+	for _, fn := range fns {
+		fn(&opts)
+	}
+	return &deadLetterDao{DAO: NewDAO[entities.DeadLetter](db, opts)}
+}
+
+func (dao *deadLetterDao) MarkReplayed(ctx context.Context, id string) error {
+	ctx, span := dao.trace(ctx, fmt.Sprintf("dao.%s.mark_replayed", dao.opts.Table))
+	defer span.End()
+
+	_, err := dao.executeUpdate(ctx, map[string]interface{}{
+		"replayed_at": sq.Expr("NOW()"),
+		"updated_at":  sq.Expr("NOW()"),
+	}, map[string]interface{}{
+		"id": id,
+	})
+	return err
+}
+
+type DeadLetterQuery struct {
+	Query
+
+	EventId    *string
+	EndpointId *string
+}
+
+func (q *DeadLetterQuery) ToQuery() *Query {
+	query := q.clone()
+	if q.EventId != nil {
+		query.Where("event_id", Equal, *q.EventId)
+	}
+	if q.EndpointId != nil {
+		query.Where("endpoint_id", Equal, *q.EndpointId)
+	}
+	return query
+}
@@ -0,0 +1,24 @@
+package dao
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+type deadLetterReplayDao struct {
+	*DAO[entities.DeadLetterReplay]
+}
+
+func NewDeadLetterReplayDao(db *sqlx.DB, fns ...OptionFunc) DeadLetterReplayDAO {
+	opts := Options{
+		Table:          "dead_letter_replays",
+		EntityName:     "dead_letter_replay",
+		CachePropagate: false,
+		CacheName:      constants.DeadLetterReplayCacheKey.Name,
+	}
+	for _, fn := range fns {
+		fn(&opts)
+	}
+	return &deadLetterReplayDao{DAO: NewDAO[entities.DeadLetterReplay](db, opts)}
+}
@@ -24,19 +24,22 @@ type DB struct {
 	DB  *sqlx.DB
 	log *zap.SugaredLogger
 
-	Workspaces       dao.WorkspaceDAO
-	Endpoints        dao.EndpointDAO
-	EndpointsWS      dao.EndpointDAO
-	Events           dao.EventDAO
-	EventsWS         dao.EventDAO
-	Attempts         dao.AttemptDAO
-	AttemptsWS       dao.AttemptDAO
-	Sources          dao.SourceDAO
-	SourcesWS        dao.SourceDAO
-	AttemptDetails   dao.AttemptDetailDAO
-	AttemptDetailsWS dao.AttemptDetailDAO
-	Plugins          dao.PluginDAO
-	PluginsWS        dao.PluginDAO
+	Workspaces          dao.WorkspaceDAO
+	Endpoints           dao.EndpointDAO
+	EndpointsWS         dao.EndpointDAO
+	Events              dao.EventDAO
+	EventsWS            dao.EventDAO
+	Attempts            dao.AttemptDAO
+	AttemptsWS          dao.AttemptDAO
+	Sources             dao.SourceDAO
+	SourcesWS           dao.SourceDAO
+	AttemptDetails      dao.AttemptDetailDAO
+	AttemptDetailsWS    dao.AttemptDetailDAO
+	Plugins             dao.PluginDAO
+	PluginsWS           dao.PluginDAO
+	DeadLetters         dao.DeadLetterDAO
+	DeadLettersWS       dao.DeadLetterDAO
+	DeadLetterReplaysWS dao.DeadLetterReplayDAO
 }
 
 func NewSqlDB(cfg modules.DatabaseConfig) (*sql.DB, error) {
@@ -76,21 +79,24 @@ func NewDB(sqlDB *sql.DB, log *zap.SugaredLogger, bus eventbus.EventBus) (*DB, e
 	}
 
 	db := &DB{
-		DB:               sqlxDB,
-		log:              log,
-		Workspaces:       dao.NewWorkspaceDAO(sqlxDB, opts...),
-		Endpoints:        dao.NewEndpointDAO(sqlxDB, opts...),
-		EndpointsWS:      dao.NewEndpointDAO(sqlxDB, append(opts, dao.WithWorkspace(true))...),
-		Events:           dao.NewEventDao(sqlxDB, opts...),
-		EventsWS:         dao.NewEventDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
-		Attempts:         dao.NewAttemptDao(sqlxDB, opts...),
-		AttemptsWS:       dao.NewAttemptDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
-		Sources:          dao.NewSourceDAO(sqlxDB, opts...),
-		SourcesWS:        dao.NewSourceDAO(sqlxDB, append(opts, dao.WithWorkspace(true))...),
-		AttemptDetails:   dao.NewAttemptDetailDao(sqlxDB, opts...),
-		AttemptDetailsWS: dao.NewAttemptDetailDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
-		Plugins:          dao.NewPluginDAO(sqlxDB, opts...),
-		PluginsWS:        dao.NewPluginDAO(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		DB:                  sqlxDB,
+		log:                 log,
+		Workspaces:          dao.NewWorkspaceDAO(sqlxDB, opts...),
+		Endpoints:           dao.NewEndpointDAO(sqlxDB, opts...),
+		EndpointsWS:         dao.NewEndpointDAO(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		Events:              dao.NewEventDao(sqlxDB, opts...),
+		EventsWS:            dao.NewEventDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		Attempts:            dao.NewAttemptDao(sqlxDB, opts...),
+		AttemptsWS:          dao.NewAttemptDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		Sources:             dao.NewSourceDAO(sqlxDB, opts...),
+		SourcesWS:           dao.NewSourceDAO(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		AttemptDetails:      dao.NewAttemptDetailDao(sqlxDB, opts...),
+		AttemptDetailsWS:    dao.NewAttemptDetailDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		Plugins:             dao.NewPluginDAO(sqlxDB, opts...),
+		PluginsWS:           dao.NewPluginDAO(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		DeadLetters:         dao.NewDeadLetterDao(sqlxDB, opts...),
+		DeadLettersWS:       dao.NewDeadLetterDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
+		DeadLetterReplaysWS: dao.NewDeadLetterReplayDao(sqlxDB, append(opts, dao.WithWorkspace(true))...),
 	}
 
 	return db, nil
@@ -18,9 +18,12 @@ type Attempt struct {
 	TriggerMode   AttemptTriggerMode `json:"trigger_mode" db:"trigger_mode"`
 	Exhausted     bool               `json:"exhausted" db:"exhausted"`
 
-	ErrorCode *AttemptErrorCode `json:"error_code" db:"error_code"`
-	Request   *AttemptRequest   `json:"request" db:"request"`
-	Response  *AttemptResponse  `json:"response" db:"response"`
+	ExhaustedReason *AttemptExhaustedReason `json:"exhausted_reason" db:"exhausted_reason"`
+
+	ErrorCode     *AttemptErrorCode     `json:"error_code" db:"error_code"`
+	Request       *AttemptRequest       `json:"request" db:"request"`
+	Response      *AttemptResponse      `json:"response" db:"response"`
+	RetryDecision *AttemptRetryDecision `json:"retry_decision" db:"retry_decision"`
 
 	Event *Event `json:"-" db:"-"`
 
@@ -50,6 +53,20 @@ const (
 	AttemptErrorCodeDenied           AttemptErrorCode = "DENIED"
 	AttemptErrorCodeEndpointNotFound AttemptErrorCode = "ENDPOINT_NOT_FOUND"
 	AttemptErrorCodeEventNotFound    AttemptErrorCode = "EVENT_NOT_FOUND"
+	AttemptErrorCodeFiltered         AttemptErrorCode = "FILTERED"
+	// AttemptErrorCodeBrokerUnavailable is the error that the message broker is not available, e.g. no leader
+	AttemptErrorCodeBrokerUnavailable AttemptErrorCode = "BROKER_UNAVAILABLE"
+	// AttemptErrorCodeAuthFailed is the error that the message broker fails to authenticate or authorize
+	AttemptErrorCodeAuthFailed AttemptErrorCode = "AUTH_FAILED"
+)
+
+type AttemptExhaustedReason = string
+
+const (
+	AttemptExhaustedReasonMaxAttempts        AttemptExhaustedReason = "MAX_ATTEMPTS"
+	AttemptExhaustedReasonNonRetryableStatus AttemptExhaustedReason = "NON_RETRYABLE_STATUS"
+	AttemptExhaustedReasonNonRetryableError  AttemptExhaustedReason = "NON_RETRYABLE_ERROR"
+	AttemptExhaustedReasonDenied             AttemptExhaustedReason = "DENIED"
 )
 
 type AttemptTriggerMode = string
@@ -80,6 +97,8 @@ type AttemptResponse struct {
 	Latency int64   `json:"latency"`
 	Headers Headers `json:"headers"`
 	Body    *string `json:"body"`
+	// BodyTruncated reports whether the body exceeds worker.deliverer.max_response_body_size and is truncated
+	BodyTruncated bool `json:"body_truncated"`
 }
 
 func (m *AttemptResponse) Scan(src interface{}) error {
@@ -89,3 +108,25 @@ func (m *AttemptResponse) Scan(src interface{}) error {
 func (m AttemptResponse) Value() (driver.Value, error) {
 	return json.Marshal(m)
 }
+
+type AttemptRetryReason = string
+
+const (
+	AttemptRetryReasonStrategy   AttemptRetryReason = "STRATEGY"
+	AttemptRetryReasonRetryAfter AttemptRetryReason = "RETRY_AFTER"
+)
+
+// AttemptRetryDecision records how the next attempt was scheduled
+type AttemptRetryDecision struct {
+	Reason     AttemptRetryReason `json:"reason"`
+	Delay      int64              `json:"delay"`
+	RetryAfter *string            `json:"retry_after"`
+}
+
+func (m *AttemptRetryDecision) Scan(src interface{}) error {
+	return json.Unmarshal(src.([]byte), m)
+}
+
+func (m AttemptRetryDecision) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"github.com/webhookx-io/webhookx/pkg/types"
+)
+
+// DeadLetter is a delivery that failed and exhausted all its attempts
+type DeadLetter struct {
+	ID         string                 `json:"id" db:"id"`
+	EventId    string                 `json:"event_id" db:"event_id"`
+	EventType  string                 `json:"event_type" db:"event_type"`
+	EndpointId string                 `json:"endpoint_id" db:"endpoint_id"`
+	AttemptId  string                 `json:"attempt_id" db:"attempt_id"`
+	Reason     AttemptExhaustedReason `json:"reason" db:"reason"`
+	ErrorCode  *AttemptErrorCode      `json:"error_code" db:"error_code"`
+	ReplayedAt *types.Time            `json:"replayed_at" db:"replayed_at"`
+
+	BaseModel
+}
+
+func (m DeadLetter) PrimaryKey() string {
+	return m.ID
+}
+
+type DeadLetterReplayStatus = string
+
+const (
+	DeadLetterReplayStatusRunning   DeadLetterReplayStatus = "RUNNING"
+	DeadLetterReplayStatusCompleted DeadLetterReplayStatus = "COMPLETED"
+	DeadLetterReplayStatusFailed    DeadLetterReplayStatus = "FAILED"
+)
+
+// DeadLetterReplay is the progress of replaying dead letters
+type DeadLetterReplay struct {
+	ID         string                 `json:"id" db:"id"`
+	Status     DeadLetterReplayStatus `json:"status" db:"status"`
+	Total      int64                  `json:"total" db:"total"`
+	Replayed   int64                  `json:"replayed" db:"replayed"`
+	Failed     int64                  `json:"failed" db:"failed"`
+	Error      *string                `json:"error" db:"error"`
+	StartedAt  types.Time             `json:"started_at" db:"started_at"`
+	FinishedAt *types.Time            `json:"finished_at" db:"finished_at"`
+
+	BaseModel
+}
+
+func (m DeadLetterReplay) PrimaryKey() string {
+	return m.ID
+}
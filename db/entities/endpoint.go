@@ -3,18 +3,34 @@ package entities
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/utils"
 )
 
 type Endpoint struct {
-	ID          string        `json:"id" db:"id"`
-	Name        *string       `json:"name" db:"name"`
-	Description *string       `json:"description" db:"description"`
-	Enabled     bool          `json:"enabled" db:"enabled"`
-	Request     RequestConfig `json:"request" db:"request"`
-	Retry       Retry         `json:"retry" db:"retry"`
-	Events      Strings       `json:"events" db:"events"`
-	Metadata    Metadata      `json:"metadata" db:"metadata"`
-	RateLimit   *RateLimit    `json:"rate_limit" db:"rate_limit"`
+	ID          string         `json:"id" db:"id"`
+	Name        *string        `json:"name" db:"name"`
+	Description *string        `json:"description" db:"description"`
+	Enabled     bool           `json:"enabled" db:"enabled"`
+	Type        EndpointType   `json:"type" db:"type"`
+	Config      EndpointConfig `json:"config" db:"config"`
+	Request     RequestConfig  `json:"request" db:"request"`
+	Retry       Retry          `json:"retry" db:"retry"`
+	Events      Strings        `json:"events" db:"events"`
+	Filter      *string        `json:"filter" db:"filter"`
+	Metadata    Metadata       `json:"metadata" db:"metadata"`
+	RateLimit   *RateLimit     `json:"rate_limit" db:"rate_limit"`
+
+	MaxConcurrency *int            `json:"max_concurrency" db:"max_concurrency"`
+	CircuitBreaker *CircuitBreaker `json:"circuit_breaker" db:"circuit_breaker"`
+	Ordering       *Ordering       `json:"ordering" db:"ordering"`
+	Delivery       *Delivery       `json:"delivery" db:"delivery"`
+	Capture        *Capture        `json:"capture" db:"capture"`
 
 	Plugins []*Plugin `json:"-" db:"-"`
 
@@ -29,11 +45,65 @@ func (m *Endpoint) SchemaName() string {
 	return "Endpoint"
 }
 
+type EndpointType string
+
+// EndpointTypeHTTP is the default endpoint type, the other types are registered by deliverers
+const EndpointTypeHTTP EndpointType = "http"
+
+// EndpointConfig holds the configuration of non-HTTP endpoints keyed by endpoint type, only the config of endpoint type
+// is used. The server address is the request url (e.g. kafka://localhost:9092), the request headers are sent as
+// message headers or gRPC metadata.
+type EndpointConfig map[string]map[string]interface{}
+
+func (m *EndpointConfig) Scan(src interface{}) error {
+	if src == nil {
+		*m = nil
+		return nil
+	}
+	return json.Unmarshal(src.([]byte), m)
+}
+
+func (m EndpointConfig) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
 type RequestConfig struct {
-	URL     string  `json:"url"`
-	Method  string  `json:"method"`
-	Headers Headers `json:"headers"`
-	Timeout int64   `json:"timeout"`
+	URL         string       `json:"url"`
+	Method      string       `json:"method"`
+	Headers     Headers      `json:"headers"`
+	Timeout     int64        `json:"timeout"`
+	TLS         *TLS         `json:"tls"`
+	Compression *Compression `json:"compression"`
+}
+
+type CompressionStage string
+
+const (
+	// CompressionStageAfterPlugins compresses the body after all outbound plugins, the signatures cover the uncompressed body
+	CompressionStageAfterPlugins CompressionStage = "after_plugins"
+	// CompressionStageBeforeSigning compresses the body before the signing plugins, the signatures cover the compressed body
+	CompressionStageBeforeSigning CompressionStage = "before_signing"
+)
+
+// Compression configures the compression of request body, the body smaller than MinSize is sent uncompressed.
+// The algorithm (gzip, zstd or deflate) is sent as Content-Encoding header.
+type Compression struct {
+	Algorithm string           `json:"algorithm"`
+	MinSize   int              `json:"min_size"`
+	Stage     CompressionStage `json:"stage"`
+}
+
+// TLS configures the TLS connection to endpoint, the certificates and key are PEM encoded
+// and can be secret references.
+type TLS struct {
+	ClientCert         *string `json:"client_cert"`
+	ClientKey          *string `json:"client_key"`
+	CACert             *string `json:"ca_cert"`
+	ServerName         *string `json:"server_name"`
+	InsecureSkipVerify bool    `json:"insecure_skip_verify"`
 }
 
 func (m *RequestConfig) Scan(src interface{}) error {
@@ -47,7 +117,8 @@ func (m RequestConfig) Value() (driver.Value, error) {
 type RetryStrategy string
 
 const (
-	RetryStrategyFixed RetryStrategy = "fixed"
+	RetryStrategyFixed       RetryStrategy = "fixed"
+	RetryStrategyExponential RetryStrategy = "exponential"
 )
 
 func (m RetryStrategy) String() string {
@@ -55,8 +126,10 @@ func (m RetryStrategy) String() string {
 }
 
 type Retry struct {
-	Strategy RetryStrategy       `json:"strategy"`
-	Config   FixedStrategyConfig `json:"config"`
+	Strategy   RetryStrategy `json:"strategy"`
+	Config     RetryConfig   `json:"config"`
+	RetryAfter *RetryAfter   `json:"retry_after"`
+	Policy     *RetryPolicy  `json:"policy"`
 }
 
 func (m *Retry) Scan(src interface{}) error {
@@ -67,6 +140,293 @@ func (m Retry) Value() (driver.Value, error) {
 	return json.Marshal(m)
 }
 
+// RetryConfig holds the configuration of all retry strategies,
+// only the fields of the configured strategy are used.
+type RetryConfig struct {
+	FixedStrategyConfig
+	ExponentialStrategyConfig
+}
+
+// RetryAfter honors the Retry-After header of 429 and 503 responses
+type RetryAfter struct {
+	MaxDelay int64 `json:"max_delay"`
+}
+
+// RetryPolicy determines which failures are retryable,
+// a failure that is not retryable exhausts the delivery immediately.
+type RetryPolicy struct {
+	StatusCodes []string           `json:"status_codes"`
+	ErrorCodes  []AttemptErrorCode `json:"error_codes"`
+}
+
+// IsRetryableStatus reports whether the response status code matches any of status codes.
+// A status code is an exact code ("429"), a class ("5xx") or a range ("500-504").
+func (m *RetryPolicy) IsRetryableStatus(code int) bool {
+	for _, s := range m.StatusCodes {
+		low, high, err := parseStatusCodeRange(s)
+		if err == nil && code >= low && code <= high {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *RetryPolicy) IsRetryableError(code AttemptErrorCode) bool {
+	return slices.Contains(m.ErrorCodes, code)
+}
+
+func parseStatusCodeRange(s string) (low int, high int, err error) {
+	if len(s) == 3 && strings.HasSuffix(s, "xx") {
+		class, err := strconv.Atoi(s[:1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid status code class: %s", s)
+		}
+		return class * 100, class*100 + 99, nil
+	}
+
+	lowStr, highStr, isRange := strings.Cut(s, "-")
+	if low, err = strconv.Atoi(lowStr); err != nil {
+		return 0, 0, fmt.Errorf("invalid status code: %s", s)
+	}
+	high = low
+	if isRange {
+		if high, err = strconv.Atoi(highStr); err != nil {
+			return 0, 0, fmt.Errorf("invalid status code: %s", s)
+		}
+		if high < low {
+			return 0, 0, fmt.Errorf("invalid status code range: %s", s)
+		}
+	}
+	return low, high, nil
+}
+
+// DefaultRetryAttempts is the delays (in seconds) of fixed strategy when the attempts are not configured
+var DefaultRetryAttempts = []int64{0, 60, 3600}
+
 type FixedStrategyConfig struct {
-	Attempts []int64 `json:"attempts"`
+	Attempts []int64 `json:"attempts,omitempty"`
+}
+
+type RetryJitter string
+
+const (
+	RetryJitterNone  RetryJitter = "none"
+	RetryJitterFull  RetryJitter = "full"
+	RetryJitterEqual RetryJitter = "equal"
+)
+
+type ExponentialStrategyConfig struct {
+	InitialDelay int64       `json:"initial_delay,omitempty"`
+	Multiplier   float64     `json:"multiplier,omitempty"`
+	MaxDelay     int64       `json:"max_delay,omitempty"`
+	MaxAttempts  int         `json:"max_attempts,omitempty"`
+	Jitter       RetryJitter `json:"jitter,omitempty"`
+}
+
+// CircuitBreaker overrides the global circuit breaker settings for the endpoint,
+// the nil thresholds fall back to the global settings.
+type CircuitBreaker struct {
+	Enabled                 bool `json:"enabled"`
+	WindowSize              *int `json:"window_size"`
+	FailureRateThreshold    *int `json:"failure_rate_threshold"`
+	MinimumRequestThreshold *int `json:"minimum_request_threshold"`
+}
+
+func (m *CircuitBreaker) Scan(src interface{}) error {
+	return json.Unmarshal(src.([]byte), m)
+}
+
+func (m CircuitBreaker) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Ordering delivers the events in order, only one event of the same ordering key is in-flight at a time,
+// and the later events wait behind the retries of earlier ones.
+type Ordering struct {
+	// Key is the JSON pointer of ordering key in event data, the events of endpoint share one sequence if nil
+	Key *string `json:"key"`
+}
+
+func (m *Ordering) Scan(src interface{}) error {
+	return json.Unmarshal(src.([]byte), m)
+}
+
+func (m Ordering) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+type DeliveryMode string
+
+const (
+	DeliveryModeSingle DeliveryMode = "single"
+	DeliveryModeBatch  DeliveryMode = "batch"
+)
+
+// Delivery configures how the events are delivered to the endpoint. In batch mode, the ready attempts
+// are coalesced into one request until one of max_events, max_bytes and max_wait is reached.
+type Delivery struct {
+	Mode      DeliveryMode `json:"mode"`
+	MaxEvents int          `json:"max_events"`
+	MaxBytes  int          `json:"max_bytes"`
+	MaxWait   int64        `json:"max_wait"`
+}
+
+func (m *Delivery) Scan(src interface{}) error {
+	return json.Unmarshal(src.([]byte), m)
+}
+
+func (m Delivery) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+type CaptureMode string
+
+const (
+	CaptureModeFull        CaptureMode = "full"
+	CaptureModeHeadersOnly CaptureMode = "headers_only"
+	CaptureModeNone        CaptureMode = "none"
+	CaptureModeOnFailure   CaptureMode = "on_failure"
+)
+
+// Capture configures what of the deliveries are stored in attempt details,
+// the values of RedactHeaders (case-insensitive) are replaced before storing.
+type Capture struct {
+	Mode          CaptureMode `json:"mode"`
+	RedactHeaders []string    `json:"redact_headers"`
+}
+
+func (m *Capture) Scan(src interface{}) error {
+	return json.Unmarshal(src.([]byte), m)
+}
+
+func (m Capture) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+const (
+	EventPatternWildcard    = "*"
+	EventPatternDescendants = "**"
+)
+
+// IsEventPattern reports whether the subscribed event is a wildcard pattern. In patterns,
+// `*` matches exactly one segment of the dot-separated event type, the trailing `**` matches one or more segments,
+// and a lone `*` matches all event types.
+func IsEventPattern(event string) bool {
+	return strings.Contains(event, EventPatternWildcard)
+}
+
+func validateEventPattern(pattern string) error {
+	if pattern == EventPatternWildcard {
+		return nil
+	}
+	segments := strings.Split(pattern, ".")
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			return fmt.Errorf("invalid event pattern: %s", pattern)
+		case segment == EventPatternDescendants:
+			if i != len(segments)-1 {
+				return fmt.Errorf("'**' must be the last segment: %s", pattern)
+			}
+		case segment != EventPatternWildcard && strings.Contains(segment, EventPatternWildcard):
+			return fmt.Errorf("wildcard must be a whole segment: %s", pattern)
+		}
+	}
+	return nil
+}
+
+// IsBatch reports whether the events are delivered in batches
+func (m *Endpoint) IsBatch() bool {
+	return m.Delivery != nil && m.Delivery.Mode == DeliveryModeBatch
+}
+
+// Init fills the defaults that depend on other fields, the fixed strategy without attempts
+// falls back to DefaultRetryAttempts.
+func (m *Endpoint) Init() {
+	if m.Retry.Strategy == RetryStrategyFixed && len(m.Retry.Config.Attempts) == 0 {
+		m.Retry.Config.Attempts = slices.Clone(DefaultRetryAttempts)
+	}
+}
+
+// EndpointValidator validates the fields of endpoint that depend on other packages (e.g. the filter or the deliverer),
+// it returns the error fields that are merged into the validation error.
+type EndpointValidator func(m *Endpoint) map[string]interface{}
+
+func (m *Endpoint) Validate(validators ...EndpointValidator) error {
+	e := errs.NewValidateError(errs.ErrRequestValidation)
+
+	retry := make(map[string]interface{})
+	switch m.Retry.Strategy {
+	case RetryStrategyFixed:
+		if len(m.Retry.Config.Attempts) == 0 {
+			retry["config"] = map[string]interface{}{"attempts": "required field missing"}
+		}
+	case RetryStrategyExponential:
+		config := m.Retry.Config.ExponentialStrategyConfig
+		if config.MaxDelay > 0 && config.MaxDelay < config.InitialDelay {
+			retry["config"] = map[string]interface{}{"max_delay": "value must be >= initial_delay"}
+		}
+	}
+
+	if m.Retry.Policy != nil {
+		fields := make([]interface{}, len(m.Retry.Policy.StatusCodes))
+		invalid := false
+		for i, code := range m.Retry.Policy.StatusCodes {
+			if _, _, err := parseStatusCodeRange(code); err != nil {
+				fields[i] = err.Error()
+				invalid = true
+			}
+		}
+		if invalid {
+			retry["policy"] = map[string]interface{}{"status_codes": fields}
+		}
+	}
+
+	if len(retry) > 0 {
+		e.Fields["retry"] = retry
+	}
+
+	fields := make([]interface{}, len(m.Events))
+	invalid := false
+	for i, event := range m.Events {
+		if IsEventPattern(event) {
+			if err := validateEventPattern(event); err != nil {
+				fields[i] = err.Error()
+				invalid = true
+			}
+		}
+	}
+	if invalid {
+		e.Fields["events"] = fields
+	}
+
+	request := make(map[string]interface{})
+	if m.Request.Compression != nil && m.Type != "" && m.Type != EndpointTypeHTTP {
+		request["compression"] = "compression is only supported by http endpoints"
+	}
+
+	if tls := m.Request.TLS; tls != nil && (tls.ClientCert == nil) != (tls.ClientKey == nil) {
+		if tls.ClientCert == nil {
+			request["tls"] = map[string]interface{}{"client_cert": "required with client_key"}
+		} else {
+			request["tls"] = map[string]interface{}{"client_key": "required with client_cert"}
+		}
+	}
+
+	if len(request) > 0 {
+		e.Fields["request"] = request
+	}
+
+	if m.IsBatch() && m.Ordering != nil {
+		e.Fields["delivery"] = map[string]interface{}{"mode": "batch mode cannot be used with ordering"}
+	}
+
+	for _, validate := range validators {
+		utils.MergeMap(e.Fields, validate(m))
+	}
+
+	if len(e.Fields) > 0 {
+		return e
+	}
+	return nil
 }
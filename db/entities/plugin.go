@@ -9,6 +9,7 @@ import (
 	"github.com/webhookx-io/webhookx/pkg/errs"
 	"github.com/webhookx-io/webhookx/pkg/license"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/secret/reference"
 )
 
 type Plugin struct {
@@ -43,12 +44,20 @@ func (m *Plugin) Validate() error {
 		return errs.NewLicenseError(fmt.Errorf("plugin '%s' is not available for current license", m.Name))
 	}
 
-	if r.Type == plugin.TypeInbound && m.SourceId == nil {
+	inbound, outbound := r.Supports(plugin.TypeInbound), r.Supports(plugin.TypeOutbound)
+	if inbound && outbound && m.SourceId == nil && m.EndpointId == nil {
+		e := errs.NewValidateError(errors.New("request validation"))
+		message := fmt.Sprintf("source_id or endpoint_id is required for plugin '%s'", m.Name)
+		e.Fields["source_id"] = message
+		e.Fields["endpoint_id"] = message
+		return e
+	}
+	if inbound && !outbound && m.SourceId == nil {
 		e := errs.NewValidateError(errors.New("request validation"))
 		e.Fields["source_id"] = fmt.Sprintf("source_id is required for plugin '%s'", m.Name)
 		return e
 	}
-	if r.Type == plugin.TypeOutbound && m.EndpointId == nil {
+	if outbound && !inbound && m.EndpointId == nil {
 		e := errs.NewValidateError(errors.New("request validation"))
 		e.Fields["endpoint_id"] = fmt.Sprintf("endpoint_id is required for plugin '%s'", m.Name)
 		return e
@@ -69,8 +78,9 @@ func (m *Plugin) Validate() error {
 		return err
 	}
 
+	// the references are resolved when the plugin is loaded
 	err = p.Init(m.Config)
-	if err != nil {
+	if err != nil && !errors.Is(err, reference.ErrReferenceUnresolved) {
 		return err
 	}
 	m.Config = p.GetConfig()
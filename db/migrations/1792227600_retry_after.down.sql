@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS ONLY "attempts" DROP COLUMN IF EXISTS "retry_decision";
@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS ONLY "attempts" ADD COLUMN IF NOT EXISTS "retry_decision" JSONB;
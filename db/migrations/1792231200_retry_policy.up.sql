@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS ONLY "attempts" ADD COLUMN IF NOT EXISTS "exhausted_reason" VARCHAR(30);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "dead_letters" (
+    "id"          CHAR(27) PRIMARY KEY,
+    "event_id"    CHAR(27) NOT NULL,
+    "event_type"  TEXT     NOT NULL,
+    "endpoint_id" CHAR(27) NOT NULL,
+    "attempt_id"  CHAR(27) NOT NULL UNIQUE,
+    "reason"      VARCHAR(30) NOT NULL,
+    "error_code"  VARCHAR(30),
+    "replayed_at" TIMESTAMPTZ(3),
+
+    "ws_id"       CHAR(27),
+    "created_at"  TIMESTAMPTZ(3) DEFAULT (CURRENT_TIMESTAMP(3) AT TIME ZONE 'UTC'),
+    "updated_at"  TIMESTAMPTZ(3) DEFAULT (CURRENT_TIMESTAMP(3) AT TIME ZONE 'UTC')
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_ws_id ON dead_letters (ws_id);
+CREATE INDEX IF NOT EXISTS idx_dead_letters_endpoint_id ON dead_letters (endpoint_id);
+CREATE INDEX IF NOT EXISTS idx_dead_letters_created_at ON dead_letters (created_at);
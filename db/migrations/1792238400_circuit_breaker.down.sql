@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS ONLY "endpoints" DROP COLUMN IF EXISTS "circuit_breaker";
@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS ONLY "endpoints" ADD COLUMN IF NOT EXISTS "delivery" JSONB;
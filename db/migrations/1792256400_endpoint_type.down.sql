@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS ONLY "endpoints" DROP COLUMN IF EXISTS "type";
+ALTER TABLE IF EXISTS ONLY "endpoints" DROP COLUMN IF EXISTS "config";
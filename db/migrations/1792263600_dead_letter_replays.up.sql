@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "dead_letter_replays" (
+    "id"          CHAR(27) PRIMARY KEY,
+    "status"      VARCHAR(20) NOT NULL,
+    "total"       BIGINT NOT NULL DEFAULT 0,
+    "replayed"    BIGINT NOT NULL DEFAULT 0,
+    "failed"      BIGINT NOT NULL DEFAULT 0,
+    "error"       TEXT,
+    "started_at"  TIMESTAMPTZ(3) NOT NULL,
+    "finished_at" TIMESTAMPTZ(3),
+
+    "ws_id"       CHAR(27),
+    "created_at"  TIMESTAMPTZ(3) DEFAULT (CURRENT_TIMESTAMP(3) AT TIME ZONE 'UTC'),
+    "updated_at"  TIMESTAMPTZ(3) DEFAULT (CURRENT_TIMESTAMP(3) AT TIME ZONE 'UTC')
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letter_replays_ws_id ON dead_letter_replays (ws_id);
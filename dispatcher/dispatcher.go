@@ -12,6 +12,8 @@ import (
 	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/services/eventbus"
 	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/ordering"
+	"github.com/webhookx-io/webhookx/worker/retry"
 	"go.uber.org/zap"
 )
 
@@ -24,10 +26,11 @@ type Dispatcher struct {
 }
 
 type Options struct {
-	DB       *db.DB
-	Metrics  *metrics.Metrics
-	Registry *Registry
-	EventBus eventbus.EventBus
+	DB        *db.DB
+	Metrics   *metrics.Metrics
+	Registry  *Registry
+	EventBus  eventbus.EventBus
+	Sequencer *ordering.Sequencer
 }
 
 func NewDispatcher(opts Options) *Dispatcher {
@@ -54,11 +57,17 @@ func (d *Dispatcher) Dispatch(ctx context.Context, events []*entities.Event) ([]
 
 	uids := make([]string, 0)
 	maps := make(map[string][]*entities.Attempt)
+	ordered := make(map[string]*entities.Endpoint)
 	for _, event := range events {
 		endpoints, err := d.registry.LookUp(ctx, event)
 		if err != nil {
 			return nil, err
 		}
+		for _, endpoint := range endpoints {
+			if endpoint.Ordering != nil {
+				ordered[endpoint.ID] = endpoint
+			}
+		}
 		if len(endpoints) != 0 {
 			attempts := fanout(event, endpoints, entities.AttemptTriggerModeInitial)
 			maps[event.ID] = attempts
@@ -112,22 +121,39 @@ func (d *Dispatcher) Dispatch(ctx context.Context, events []*entities.Event) ([]
 		if d.opts.Metrics.Enabled {
 			d.opts.Metrics.EventPersistCounter.Add(float64(n))
 		}
+		d.sequence(ctx, attempts, ordered)
 	}
 	return attempts, err
 }
 
+// sequence appends the attempts of ordered endpoints to their sequences
+func (d *Dispatcher) sequence(ctx context.Context, attempts []*entities.Attempt, ordered map[string]*entities.Endpoint) {
+	if d.opts.Sequencer == nil || len(ordered) == 0 {
+		return
+	}
+	for _, attempt := range attempts {
+		endpoint, ok := ordered[attempt.EndpointId]
+		if !ok {
+			continue
+		}
+		if err := d.opts.Sequencer.Enqueue(ctx, endpoint, attempt.Event); err != nil {
+			d.log.Warnf("failed to sequence event %s for endpoint %s: %v", attempt.EventId, endpoint.ID, err)
+		}
+	}
+}
+
 func fanout(event *entities.Event, endpoints []*entities.Endpoint, mode entities.AttemptTriggerMode) []*entities.Attempt {
 	attempts := make([]*entities.Attempt, 0, len(endpoints))
 	now := time.Now()
 	for _, endpoint := range endpoints {
-		delay := endpoint.Retry.Config.Attempts[0]
+		delay := retry.FromEndpoint(endpoint).NextDelay(1)
 		attempt := &entities.Attempt{
 			ID:            utils.KSUID(),
 			EventId:       event.ID,
 			EndpointId:    endpoint.ID,
 			Status:        entities.AttemptStatusInit,
 			AttemptNumber: 1,
-			ScheduledAt:   types.NewTime(now.Add(time.Second * time.Duration(delay))),
+			ScheduledAt:   types.NewTime(now.Add(delay)),
 			TriggerMode:   mode,
 			Event:         event,
 		}
@@ -145,6 +171,14 @@ func (d *Dispatcher) DispatchEndpoint(ctx context.Context, event *entities.Event
 		return nil, err
 	}
 
+	ordered := make(map[string]*entities.Endpoint)
+	for _, endpoint := range endpoints {
+		if endpoint.Ordering != nil {
+			ordered[endpoint.ID] = endpoint
+		}
+	}
+	d.sequence(ctx, attempts, ordered)
+
 	return attempts, nil
 }
 
@@ -1,19 +1,60 @@
 package dispatcher
 
-import "github.com/webhookx-io/webhookx/db/entities"
+import (
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/filter"
+	"go.uber.org/zap"
+)
 
 type Registration struct {
-	static map[string][]*entities.Endpoint
+	static   map[string][]*entities.Endpoint
+	patterns *trie
+	all      []*entities.Endpoint
+	filters  map[string]*filter.Filter
+}
+
+// filters caches the compiled filters by expression, so that a filter is compiled once rather than every time
+// the registration of workspace is reloaded
+var filters, _ = lru.New[string, *filter.Filter](1024)
+
+func compileFilter(expr string) (*filter.Filter, error) {
+	if f, ok := filters.Get(expr); ok {
+		return f, nil
+	}
+	f, err := filter.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	filters.Add(expr, f)
+	return f, nil
 }
 
 func NewRegistration(endpoints []*entities.Endpoint) *Registration {
 	r := &Registration{
-		static: make(map[string][]*entities.Endpoint),
+		static:   make(map[string][]*entities.Endpoint),
+		patterns: newTrie(),
+		filters:  make(map[string]*filter.Filter),
 	}
 
 	for _, endpoint := range endpoints {
+		if endpoint.Filter != nil {
+			f, err := compileFilter(*endpoint.Filter)
+			if err != nil {
+				zap.S().Warnf("endpoint %s is ignored due to invalid filter: %v", endpoint.ID, err)
+				continue
+			}
+			r.filters[endpoint.ID] = f
+		}
 		for _, event := range endpoint.Events {
-			r.static[event] = append(r.static[event], endpoint)
+			switch {
+			case event == entities.EventPatternWildcard:
+				r.all = append(r.all, endpoint)
+			case entities.IsEventPattern(event):
+				r.patterns.insert(event, endpoint)
+			default:
+				r.static[event] = append(r.static[event], endpoint)
+			}
 		}
 	}
 	return r
@@ -21,5 +62,54 @@ func NewRegistration(endpoints []*entities.Endpoint) *Registration {
 
 func (r *Registration) LookUp(event *entities.Event) []*entities.Endpoint {
 	matched := r.static[event.EventType]
-	return matched
+	if patterns := r.patterns.match(event.EventType); len(patterns) > 0 || len(r.all) > 0 {
+		matched = dedupe(matched, r.all, patterns)
+	}
+	if len(r.filters) == 0 {
+		return matched
+	}
+
+	// the data is decoded once and lazily, only when a matched endpoint has a filter
+	var data interface{}
+	var decodeErr error
+	decoded := false
+
+	accepted := make([]*entities.Endpoint, 0, len(matched))
+	for _, endpoint := range matched {
+		if f, ok := r.filters[endpoint.ID]; ok {
+			if !decoded {
+				data, decodeErr = filter.Decode(event.Data)
+				decoded = true
+			}
+			if decodeErr != nil {
+				zap.S().Warnf("event %s is rejected by filter of endpoint %s: %v", event.ID, endpoint.ID, decodeErr)
+				continue
+			}
+			ok, err := f.Match(event.EventType, data)
+			if err != nil {
+				zap.S().Warnf("event %s is rejected by filter of endpoint %s: %v", event.ID, endpoint.ID, err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		accepted = append(accepted, endpoint)
+	}
+	return accepted
+}
+
+// dedupe merges the endpoints matched by event type and patterns,
+// an endpoint subscribing multiple matching patterns appears once.
+func dedupe(lists ...[]*entities.Endpoint) []*entities.Endpoint {
+	seen := make(map[string]bool)
+	endpoints := make([]*entities.Endpoint, 0)
+	for _, list := range lists {
+		for _, endpoint := range list {
+			if !seen[endpoint.ID] {
+				seen[endpoint.ID] = true
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	return endpoints
 }
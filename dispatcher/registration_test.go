@@ -0,0 +1,69 @@
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+func endpoint(id string, events ...string) *entities.Endpoint {
+	return &entities.Endpoint{ID: id, Events: events}
+}
+
+func ids(endpoints []*entities.Endpoint) []string {
+	list := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		list = append(list, endpoint.ID)
+	}
+	return list
+}
+
+func TestRegistrationLookUp(t *testing.T) {
+	r := NewRegistration([]*entities.Endpoint{
+		endpoint("static", "order.created"),
+		endpoint("one", "order.*"),
+		endpoint("suffix", "*.created"),
+		endpoint("descendants", "order.**"),
+		endpoint("all", "*"),
+		endpoint("multiple", "order.created", "order.*", "*.created"),
+	})
+
+	tests := []struct {
+		eventType string
+		expected  []string
+	}{
+		{"order.created", []string{"static", "multiple", "all", "descendants", "one", "suffix"}},
+		{"order.updated", []string{"all", "descendants", "one", "multiple"}},
+		{"order.item.created", []string{"all", "descendants"}},
+		{"order", []string{"all"}},
+		{"user.created", []string{"all", "suffix", "multiple"}},
+		{"user.deleted", []string{"all"}},
+	}
+	for _, test := range tests {
+		matched := r.LookUp(&entities.Event{EventType: test.eventType})
+		assert.ElementsMatch(t, test.expected, ids(matched), test.eventType)
+	}
+}
+
+func TestRegistrationLookUpFilter(t *testing.T) {
+	e := endpoint("filtered", "order.*")
+	e.Filter = new(`data.amount > 100`)
+	r := NewRegistration([]*entities.Endpoint{e, endpoint("all", "*")})
+
+	matched := r.LookUp(&entities.Event{EventType: "order.created", Data: []byte(`{"amount": 101}`)})
+	assert.ElementsMatch(t, []string{"all", "filtered"}, ids(matched))
+	matched = r.LookUp(&entities.Event{EventType: "order.created", Data: []byte(`{"amount": 100}`)})
+	assert.ElementsMatch(t, []string{"all"}, ids(matched))
+}
+
+func TestRegistrationCompilesFilterOnce(t *testing.T) {
+	e := endpoint("filtered", "order.*")
+	e.Filter = new(`data.amount > 200`)
+	NewRegistration([]*entities.Endpoint{e})
+	f, ok := filters.Get(*e.Filter)
+	assert.True(t, ok)
+
+	r := NewRegistration([]*entities.Endpoint{e})
+	assert.Same(t, f, r.filters[e.ID])
+}
@@ -0,0 +1,72 @@
+package dispatcher
+
+import (
+	"strings"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+// trie matches the event types against the wildcard patterns segment by segment,
+// `*` matches exactly one segment and the trailing `**` matches one or more segments.
+type trie struct {
+	root *node
+}
+
+type node struct {
+	children    map[string]*node
+	wildcard    *node                // *
+	endpoints   []*entities.Endpoint // the patterns end at the node
+	descendants []*entities.Endpoint // the patterns end with ** at the node
+}
+
+func newNode() *node {
+	return &node{children: make(map[string]*node)}
+}
+
+func newTrie() *trie {
+	return &trie{root: newNode()}
+}
+
+func (t *trie) insert(pattern string, endpoint *entities.Endpoint) {
+	n := t.root
+	for _, segment := range strings.Split(pattern, ".") {
+		switch segment {
+		case entities.EventPatternDescendants:
+			n.descendants = append(n.descendants, endpoint)
+			return
+		case entities.EventPatternWildcard:
+			if n.wildcard == nil {
+				n.wildcard = newNode()
+			}
+			n = n.wildcard
+		default:
+			child, ok := n.children[segment]
+			if !ok {
+				child = newNode()
+				n.children[segment] = child
+			}
+			n = child
+		}
+	}
+	n.endpoints = append(n.endpoints, endpoint)
+}
+
+func (t *trie) match(eventType string) []*entities.Endpoint {
+	var matched []*entities.Endpoint
+	t.root.match(strings.Split(eventType, "."), &matched)
+	return matched
+}
+
+func (n *node) match(segments []string, matched *[]*entities.Endpoint) {
+	if len(segments) == 0 {
+		*matched = append(*matched, n.endpoints...)
+		return
+	}
+	*matched = append(*matched, n.descendants...)
+	if child, ok := n.children[segments[0]]; ok {
+		child.match(segments[1:], matched)
+	}
+	if n.wildcard != nil {
+		n.wildcard.match(segments[1:], matched)
+	}
+}
@@ -75,6 +75,9 @@ type Callback[T any] func(ctx context.Context, id string) (*T, error)
 
 type LoadOptions struct {
 	DisableLRU bool
+	// TTL returns the L2 TTL of loaded value, DefaultL2TTL is used if nil.
+	// The value is not cached if the TTL is not positive.
+	TTL func(value any) time.Duration
 }
 
 var defaultOpts LoadOptions
@@ -116,7 +119,15 @@ func Load[T any](ctx context.Context, key string, opts *LoadOptions, cb Callback
 		return value, err
 	}
 
-	err = mcache.l2.Put(ctx, key, value, DefaultL2TTL)
+	ttl := DefaultL2TTL
+	if opts.TTL != nil {
+		ttl = opts.TTL(value)
+		if ttl <= 0 {
+			return value, nil
+		}
+	}
+
+	err = mcache.l2.Put(ctx, key, value, ttl)
 	if err != nil {
 		return nil, err
 	}
@@ -13,11 +13,13 @@ import (
 )
 
 type MockCache struct {
-	data map[string]interface{}
+	data        map[string]interface{}
+	expirations map[string]time.Duration
 }
 
 func (m *MockCache) Put(ctx context.Context, key string, val interface{}, expiration time.Duration) error {
 	m.data[key] = val
+	m.expirations[key] = expiration
 	return nil
 }
 
@@ -71,7 +73,8 @@ var _ = Describe("mcache", Ordered, func() {
 		}
 
 		mockCache = &MockCache{
-			data: make(map[string]interface{}),
+			data:        make(map[string]interface{}),
+			expirations: make(map[string]time.Duration),
 		}
 
 		mcache = NewMCache(&Options{
@@ -130,6 +133,25 @@ var _ = Describe("mcache", Ordered, func() {
 		assert.EqualValues(GinkgoT(), 0, n.Load()-before)
 	})
 
+	It("ttl", func() {
+		opts := &LoadOptions{TTL: func(value any) time.Duration { return time.Minute * 5 }}
+		value, err := Load(context.TODO(), "foo", opts, testDao.Get, "foo")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), "bar", *value)
+		assert.Equal(GinkgoT(), time.Minute*5, mockCache.expirations["foo"])
+
+		// value should not be cached when ttl is not positive
+		mcache.Invalidate(context.TODO(), "foo")
+		opts = &LoadOptions{TTL: func(value any) time.Duration { return 0 }}
+		value, err = Load(context.TODO(), "foo", opts, testDao.Get, "foo")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), "bar", *value)
+		_, ok := mockCache.data["foo"]
+		assert.False(GinkgoT(), ok)
+		_, ok = mcache.l1.Get("foo")
+		assert.False(GinkgoT(), ok)
+	})
+
 })
 
 func Test(t *testing.T) {
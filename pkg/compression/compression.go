@@ -0,0 +1,94 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	Gzip    = "gzip"
+	Zstd    = "zstd"
+	Deflate = "deflate"
+)
+
+var (
+	// encoder is shared by all compressions, EncodeAll is safe for concurrent use
+	encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+
+	gzipWriters = sync.Pool{
+		New: func() interface{} {
+			return gzip.NewWriter(nil)
+		},
+	}
+	// zlibWriters writes the deflate encoding, which is the zlib format (RFC 1950) rather than raw DEFLATE in HTTP
+	zlibWriters = sync.Pool{
+		New: func() interface{} {
+			return zlib.NewWriter(nil)
+		},
+	}
+)
+
+// resetWriter is implemented by the gzip and zlib writers
+type resetWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// Compress compresses data with algorithm, the algorithm is the value of Content-Encoding header
+func Compress(algorithm string, data []byte) ([]byte, error) {
+	var pool *sync.Pool
+	switch algorithm {
+	case Gzip:
+		pool = &gzipWriters
+	case Zstd:
+		return encoder.EncodeAll(data, nil), nil
+	case Deflate:
+		pool = &zlibWriters
+	default:
+		return nil, fmt.Errorf("unsupported compression algorithm: %s", algorithm)
+	}
+
+	w := pool.Get().(resetWriter)
+	defer pool.Put(w)
+
+	var buf bytes.Buffer
+	w.Reset(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses data that is compressed with algorithm
+func Decompress(algorithm string, data []byte) ([]byte, error) {
+	var r io.ReadCloser
+	var err error
+	switch algorithm {
+	case Gzip:
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	case Zstd:
+		var d *zstd.Decoder
+		d, err = zstd.NewReader(bytes.NewReader(data))
+		if err == nil {
+			r = d.IOReadCloser()
+		}
+	case Deflate:
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported compression algorithm: %s", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
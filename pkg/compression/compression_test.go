@@ -0,0 +1,56 @@
+package compression
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompress(t *testing.T) {
+	data := bytes.Repeat([]byte(`{"foo":"bar"}`), 100)
+	for _, algorithm := range []string{Gzip, Zstd, Deflate} {
+		compressed, err := Compress(algorithm, data)
+		assert.NoError(t, err, algorithm)
+		assert.Less(t, len(compressed), len(data), algorithm)
+
+		decompressed, err := Decompress(algorithm, compressed)
+		assert.NoError(t, err, algorithm)
+		assert.Equal(t, data, decompressed, algorithm)
+	}
+
+	// the deflate encoding is the zlib format
+	compressed, err := Compress(Deflate, data)
+	assert.NoError(t, err)
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	assert.NoError(t, err)
+	decompressed, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decompressed)
+
+	_, err = Compress("br", data)
+	assert.EqualError(t, err, "unsupported compression algorithm: br")
+	_, err = Decompress("br", data)
+	assert.EqualError(t, err, "unsupported compression algorithm: br")
+}
+
+func TestCompressConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Go(func() {
+			data := bytes.Repeat([]byte(fmt.Sprintf(`{"n":%d}`, i)), 100)
+			for _, algorithm := range []string{Gzip, Zstd, Deflate} {
+				compressed, err := Compress(algorithm, data)
+				assert.NoError(t, err, algorithm)
+				decompressed, err := Decompress(algorithm, compressed)
+				assert.NoError(t, err, algorithm)
+				assert.Equal(t, data, decompressed, algorithm)
+			}
+		})
+	}
+	wg.Wait()
+}
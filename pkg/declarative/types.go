@@ -32,6 +32,7 @@ func (cfg *Configuration) Init() {
 		if m.ID == "" {
 			m.ID = utils.KSUID()
 		}
+		m.Endpoint.Init()
 		for _, p := range m.Plugins {
 			if p.ID == "" {
 				p.ID = utils.KSUID()
@@ -41,13 +42,17 @@ func (cfg *Configuration) Init() {
 	}
 }
 
-func (cfg *Configuration) Validate() error {
+// Validate validates the configuration, the endpoints are validated with validators in addition
+func (cfg *Configuration) Validate(validators ...entities.EndpointValidator) error {
 	err := utils.Validate(cfg)
 	if err != nil {
 		return err
 	}
 
 	for _, end := range cfg.Endpoints {
+		if err := end.Endpoint.Validate(validators...); err != nil {
+			return err
+		}
 		for _, model := range end.Plugins {
 			if err := model.Validate(); err != nil {
 				return err
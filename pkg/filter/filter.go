@@ -0,0 +1,116 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/dop251/goja/ast"
+)
+
+// Timeout is the maximum time of evaluating a filter
+var Timeout = time.Millisecond * 100
+
+var ErrNotExpression = errors.New("filter must be a single expression")
+
+// Filter is a compiled JavaScript expression that decides whether an event is accepted,
+// e.g. data.amount > 100 && data.currency === "USD".
+// The expression is evaluated with the globals `event_type` and `data` (the decoded event data).
+type Filter struct {
+	expr     string
+	program  *goja.Program
+	runtimes sync.Pool
+}
+
+// Compile compiles the expression, the compiled filter is safe for concurrent use
+func Compile(expr string) (*Filter, error) {
+	prg, err := goja.Parse("filter", expr)
+	if err != nil {
+		return nil, err
+	}
+	if !isExpression(prg) {
+		return nil, ErrNotExpression
+	}
+	program, err := goja.CompileAST(prg, true)
+	if err != nil {
+		return nil, err
+	}
+	f := &Filter{expr: expr, program: program}
+	f.runtimes.New = func() interface{} { return goja.New() }
+	return f, nil
+}
+
+func isExpression(program *ast.Program) bool {
+	if len(program.Body) != 1 {
+		return false
+	}
+	_, ok := program.Body[0].(*ast.ExpressionStatement)
+	return ok
+}
+
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Decode decodes the event data for Match, the data can be decoded once and matched against multiple filters
+func Decode(data []byte) (interface{}, error) {
+	var v interface{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("invalid event data: %w", err)
+		}
+	}
+	return v, nil
+}
+
+// Match evaluates the filter against the event data decoded by Decode, the result is converted to boolean.
+// The runtimes are pooled per filter and each evaluation has its own copy of data, so that a filter cannot
+// affect others (e.g. by changing Object.prototype or the data). The runtime failed to evaluate is discarded.
+func (f *Filter) Match(eventType string, data interface{}) (bool, error) {
+	vm := f.runtimes.Get().(*goja.Runtime)
+	if err := vm.Set("event_type", eventType); err != nil {
+		return false, err
+	}
+	if err := vm.Set("data", toValue(vm, data)); err != nil {
+		return false, err
+	}
+
+	timer := time.AfterFunc(Timeout, func() { vm.Interrupt(errors.New("timeout")) })
+	value, err := vm.RunProgram(f.program)
+	interrupted := !timer.Stop()
+	if err != nil {
+		var e *goja.InterruptedError
+		if errors.As(err, &e) {
+			return false, fmt.Errorf("failed to evaluate filter: %v", e.Value())
+		}
+		return false, fmt.Errorf("failed to evaluate filter: %w", err)
+	}
+	matched := value.ToBoolean()
+	if !interrupted {
+		f.runtimes.Put(vm)
+	}
+	return matched, nil
+}
+
+// toValue converts the decoded JSON into native objects of runtime, instead of wrapping the Go values
+// that are shared across evaluations.
+func toValue(vm *goja.Runtime, v interface{}) goja.Value {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		obj := vm.NewObject()
+		for key, value := range v {
+			_ = obj.Set(key, toValue(vm, value))
+		}
+		return obj
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, value := range v {
+			items[i] = toValue(vm, value)
+		}
+		return vm.NewArray(items...)
+	}
+	return vm.ToValue(v)
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expr      string
+		eventType string
+		data      string
+		expected  bool
+		err       bool
+	}{
+		{expr: `data.amount > 100 && data.currency === "USD"`, data: `{"amount": 101, "currency": "USD"}`, expected: true},
+		{expr: `data.amount > 100 && data.currency === "USD"`, data: `{"amount": 100, "currency": "USD"}`, expected: false},
+		{expr: `data.amount > 100 && data.currency === "USD"`, data: `{"amount": 101, "currency": "EUR"}`, expected: false},
+		{expr: `event_type.startsWith("charge.")`, eventType: "charge.succeeded", data: `{}`, expected: true},
+		{expr: `data.tags.includes("vip")`, data: `{"tags": ["new", "vip"]}`, expected: true},
+		{expr: `data.customer`, data: `{"customer": "c1"}`, expected: true},
+		{expr: `data.customer`, data: `{}`, expected: false},
+		{expr: `data.customer.id === "c1"`, data: `{}`, err: true},
+		{expr: `data.amount > 0`, data: `invalid`, err: true},
+	}
+	for _, test := range tests {
+		f, err := Compile(test.expr)
+		assert.NoError(t, err)
+		data, err := Decode([]byte(test.data))
+		if err == nil {
+			var matched bool
+			matched, err = f.Match(test.eventType, data)
+			assert.Equal(t, test.expected, matched, test.expr)
+		}
+		assert.Equal(t, test.err, err != nil, test.expr)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	_, err := Compile(`data.amount >`)
+	assert.Error(t, err)
+
+	_, err = Compile(`data.amount > 1; data.amount < 2`)
+	assert.Equal(t, ErrNotExpression, err)
+
+	_, err = Compile(`var a = 1`)
+	assert.Equal(t, ErrNotExpression, err)
+}
+
+func TestMatchTimeout(t *testing.T) {
+	f, err := Compile(`(() => { while (true) {} })()`)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	matched, err := f.Match("foo.bar", map[string]interface{}{})
+	assert.False(t, matched)
+	assert.EqualError(t, err, "failed to evaluate filter: timeout")
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the filters are evaluable after interrupted
+	f, err = Compile(`true`)
+	assert.NoError(t, err)
+	matched, err = f.Match("foo.bar", map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, matched)
+}
+
+func TestMatchIsolation(t *testing.T) {
+	data, err := Decode([]byte(`{"amount": 1, "tags": ["new"]}`))
+	assert.NoError(t, err)
+
+	polluter, err := Compile(`(Object.prototype.currency = "USD", data.amount = 1000, data.tags.push("vip"), false)`)
+	assert.NoError(t, err)
+	matched, err := polluter.Match("foo.bar", data)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+
+	f, err := Compile(`data.currency === "USD" || data.amount > 1 || data.tags.includes("vip")`)
+	assert.NoError(t, err)
+	matched, err = f.Match("foo.bar", data)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func TestMatchReusesRuntime(t *testing.T) {
+	f, err := Compile(`data.amount > 100`)
+	assert.NoError(t, err)
+	for _, amount := range []float64{101, 100, 101} {
+		matched, err := f.Match("foo.bar", map[string]interface{}{"amount": amount})
+		assert.NoError(t, err)
+		assert.Equal(t, amount > 100, matched)
+	}
+}
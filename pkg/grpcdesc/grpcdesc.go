@@ -0,0 +1,188 @@
+package grpcdesc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var ErrInvalidMethod = errors.New("method must be in the form of package.Service/Method")
+
+// SplitMethod splits the full method name (e.g. acme.v1.Service/Method) into service and method names
+func SplitMethod(fullMethod string) (service protoreflect.FullName, method protoreflect.Name, err error) {
+	s, m, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	service, method = protoreflect.FullName(s), protoreflect.Name(m)
+	if !ok || !service.IsValid() || !method.IsValid() {
+		return "", "", ErrInvalidMethod
+	}
+	return service, method, nil
+}
+
+// ParseDescriptorSet parses the base64 encoded FileDescriptorSet.
+// The imports missing in the set are resolved from the well-known types.
+func ParseDescriptorSet(b64 string) (*protoregistry.Files, error) {
+	b, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return nil, fmt.Errorf("invalid FileDescriptorSet: %w", err)
+	}
+	return BuildFiles(set.File)
+}
+
+// BuildFiles builds the files registry from file descriptors in any order
+func BuildFiles(fds []*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	byName := make(map[string]*descriptorpb.FileDescriptorProto, len(fds))
+	for _, fd := range fds {
+		byName[fd.GetName()] = fd
+	}
+
+	files := &protoregistry.Files{}
+	var build func(name string, path []string) error
+	build = func(name string, path []string) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+		fd, ok := byName[name]
+		if !ok {
+			wkt, err := protoregistry.GlobalFiles.FindFileByPath(name)
+			if err != nil {
+				return fmt.Errorf("missing file descriptor: %s", name)
+			}
+			return files.RegisterFile(wkt)
+		}
+		for _, p := range path {
+			if p == name {
+				return fmt.Errorf("import cycle in file descriptor: %s", name)
+			}
+		}
+		for _, dep := range fd.GetDependency() {
+			if err := build(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		file, err := protodesc.NewFile(fd, files)
+		if err != nil {
+			return err
+		}
+		return files.RegisterFile(file)
+	}
+
+	for _, fd := range fds {
+		if err := build(fd.GetName(), nil); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// FindMethod finds the descriptor of unary method in files
+func FindMethod(files *protoregistry.Files, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := SplitMethod(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := files.FindDescriptorByName(serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found", serviceName)
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", serviceName)
+	}
+	method := service.Methods().ByName(methodName)
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, serviceName)
+	}
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, fmt.Errorf("method %s is not unary", method.FullName())
+	}
+	return method, nil
+}
+
+// Reflect resolves the descriptor of unary method via the server reflection (grpc.reflection.v1)
+func Reflect(ctx context.Context, conn grpc.ClientConnInterface, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	serviceName, _, err := SplitMethod(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	fds := make(map[string]*descriptorpb.FileDescriptorProto)
+	requested := make(map[string]bool)
+	request := &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: string(serviceName)},
+	}
+	for request != nil {
+		if err := stream.Send(request); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return nil, fmt.Errorf("server reflection error: %s", e.GetErrorMessage())
+		}
+		for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(b, fd); err != nil {
+				return nil, err
+			}
+			fds[fd.GetName()] = fd
+		}
+
+		// servers may omit the dependencies sent before, request the missing ones one by one
+		request = nil
+		for _, fd := range fds {
+			for _, dep := range fd.GetDependency() {
+				if _, ok := fds[dep]; ok {
+					continue
+				}
+				if _, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+					continue
+				}
+				if requested[dep] {
+					return nil, fmt.Errorf("missing file descriptor: %s", dep)
+				}
+				requested[dep] = true
+				request = &reflectionpb.ServerReflectionRequest{
+					MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+				}
+				break
+			}
+			if request != nil {
+				break
+			}
+		}
+	}
+
+	list := make([]*descriptorpb.FileDescriptorProto, 0, len(fds))
+	for _, fd := range fds {
+		list = append(list, fd)
+	}
+	files, err := BuildFiles(list)
+	if err != nil {
+		return nil, err
+	}
+	return FindMethod(files, fullMethod)
+}
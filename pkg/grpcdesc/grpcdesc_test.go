@@ -0,0 +1,75 @@
+package grpcdesc
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestSplitMethod(t *testing.T) {
+	service, method, err := SplitMethod("/acme.v1.Service/Method")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "acme.v1.Service", service)
+	assert.EqualValues(t, "Method", method)
+
+	for _, name := range []string{"", "Service", "acme.v1.Service/", "/Method", "acme.v1.Service/Method/Foo", "acme-v1.Service/Method"} {
+		_, _, err := SplitMethod(name)
+		assert.Equal(t, ErrInvalidMethod, err, name)
+	}
+}
+
+func TestParseDescriptorSet(t *testing.T) {
+	encode := func(fds ...*descriptorpb.FileDescriptorProto) string {
+		b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: fds})
+		assert.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(b)
+	}
+
+	files, err := ParseDescriptorSet(encode(protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto)))
+	assert.NoError(t, err)
+	method, err := FindMethod(files, "grpc.health.v1.Health/Check")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "grpc.health.v1.HealthCheckRequest", method.Input().FullName())
+
+	_, err = FindMethod(files, "grpc.health.v1.Health/Watch")
+	assert.EqualError(t, err, "method grpc.health.v1.Health.Watch is not unary")
+	_, err = FindMethod(files, "grpc.health.v1.Unknown/Check")
+	assert.EqualError(t, err, "service grpc.health.v1.Unknown not found")
+	_, err = FindMethod(files, "grpc.health.v1.HealthCheckRequest/Check")
+	assert.EqualError(t, err, "grpc.health.v1.HealthCheckRequest is not a service")
+
+	// imports of well-known types are resolved
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("acme.proto"),
+		Package:    proto.String("acme"),
+		Dependency: []string{"google/protobuf/struct.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Webhook"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Handle"),
+				InputType:  proto.String(".google.protobuf.Struct"),
+				OutputType: proto.String(".google.protobuf.Struct"),
+			}},
+		}},
+	}
+	files, err = ParseDescriptorSet(encode(fd))
+	assert.NoError(t, err)
+	method, err = FindMethod(files, "acme.Webhook/Handle")
+	assert.NoError(t, err)
+	assert.Equal(t, (&structpb.Struct{}).ProtoReflect().Descriptor().FullName(), method.Input().FullName())
+
+	fd.Dependency = []string{"acme/missing.proto"}
+	_, err = ParseDescriptorSet(encode(fd))
+	assert.EqualError(t, err, "missing file descriptor: acme/missing.proto")
+
+	_, err = ParseDescriptorSet("!")
+	assert.ErrorContains(t, err, "invalid base64")
+	_, err = ParseDescriptorSet(base64.StdEncoding.EncodeToString([]byte("invalid")))
+	assert.ErrorContains(t, err, "invalid FileDescriptorSet")
+}
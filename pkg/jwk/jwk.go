@@ -0,0 +1,65 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// Key is a public JSON Web Key (RFC 7517)
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// OKP (RFC 8037)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+
+	// RSA (RFC 7518)
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+}
+
+// Set is a JSON Web Key Set
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// New returns the JWK of public key, only Ed25519 and RSA keys are supported
+func New(pub crypto.PublicKey) (Key, error) {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return Key{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+		}, nil
+	case *rsa.PublicKey:
+		return Key{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported public key type: %T", pub)
+	}
+}
+
+// Thumbprint returns the base64url encoded SHA-256 thumbprint of key (RFC 7638)
+func (k Key) Thumbprint() string {
+	var s string
+	switch k.Kty {
+	case "OKP":
+		s = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q}`, k.Crv, k.Kty, k.X)
+	case "RSA":
+		s = fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, k.E, k.Kty, k.N)
+	}
+	sum := sha256.Sum256([]byte(s))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
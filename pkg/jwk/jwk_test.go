@@ -0,0 +1,41 @@
+package jwk
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Ed25519", func(t *testing.T) {
+		// RFC 8037 Appendix A
+		x, _ := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+		key, err := New(ed25519.PublicKey(x))
+		assert.NoError(t, err)
+		assert.Equal(t, "OKP", key.Kty)
+		assert.Equal(t, "Ed25519", key.Crv)
+		assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", key.Thumbprint())
+	})
+
+	t.Run("RSA", func(t *testing.T) {
+		pk, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		key, err := New(&pk.PublicKey)
+		assert.NoError(t, err)
+		key.Kid = "foo"
+		b, err := json.Marshal(key)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"kty":"RSA","kid":"foo","n":"`+key.N+`","e":"AQAB"}`, string(b))
+		assert.Len(t, key.Thumbprint(), 43)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := New("foo")
+		assert.EqualError(t, err, "unsupported public key type: string")
+	})
+}
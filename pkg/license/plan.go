@@ -28,7 +28,11 @@ type Condition struct {
 var (
 	FreePlugins = []string{
 		"webhookx-signature",
+		"standard-webhooks",
+		"asymmetric-signature",
+		"oauth2",
 		"wasm",
+		"transform",
 		"function",
 		"basic-auth",
 		"key-auth",
@@ -47,18 +51,19 @@ var plans = map[string]Plan{
 		Plugins:  FreePlugins,
 		Features: []string{},
 		ForbiddenAPIs: map[string]*Condition{
-			"/workspaces":                               {Methods: []string{"POST"}},
-			"/workspaces/{id}":                          {Methods: []string{"DELETE"}},
-			"/workspaces/{workspace}/config/sync":       {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/config/dump":       {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/endpoints":         {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/endpoints/{id}":    {Methods: []string{"PUT", "DELETE"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/sources":           {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/sources/{id}":      {Methods: []string{"PUT", "DELETE"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/events":            {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/events/{id}/retry": {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/plugins":           {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
-			"/workspaces/{workspace}/plugins/{id}":      {Methods: []string{"PUT", "DELETE"}, ExcludeDefaultWorkspace: true},
+			"/workspaces":                                 {Methods: []string{"POST"}},
+			"/workspaces/{id}":                            {Methods: []string{"DELETE"}},
+			"/workspaces/{workspace}/config/sync":         {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/config/dump":         {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/endpoints":           {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/endpoints/{id}":      {Methods: []string{"PUT", "DELETE"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/sources":             {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/sources/{id}":        {Methods: []string{"PUT", "DELETE"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/events":              {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/events/{id}/retry":   {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/plugins":             {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/plugins/{id}":        {Methods: []string{"PUT", "DELETE"}, ExcludeDefaultWorkspace: true},
+			"/workspaces/{workspace}/plugins/{id}/rotate": {Methods: []string{"POST"}, ExcludeDefaultWorkspace: true},
 		},
 		Limits: map[string]int{},
 	},
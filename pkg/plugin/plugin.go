@@ -2,9 +2,12 @@ package plugin
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/webhookx-io/webhookx/pkg/http/response"
+	"github.com/webhookx-io/webhookx/pkg/jwk"
 )
 
 type Plugin interface {
@@ -30,6 +33,32 @@ type Plugin interface {
 	ExecuteOutbound(c *Context) error
 }
 
+// OutboundResponseHandler is implemented by the outbound plugins that handle the response of delivery
+type OutboundResponseHandler interface {
+	// HandleOutboundResponse is called after the request is delivered,
+	// it returns true to deliver the request again (only once).
+	HandleOutboundResponse(c *Context, statusCode int) (bool, error)
+}
+
+// Signer is implemented by the outbound plugins that sign the request body,
+// the request body is compressed before them if the endpoint asks to.
+type Signer interface {
+	// SignsBody reports whether the plugin signs the request body
+	SignsBody() bool
+}
+
+// SecretRotator is implemented by the plugins whose secret can be rotated
+type SecretRotator interface {
+	// RotateSecret generates a new secret, the current secret remains active until it expires after overlap.
+	RotateSecret(now time.Time, overlap time.Duration)
+}
+
+// PublicKeyProvider is implemented by the plugins that publish public keys for verifying their signatures
+type PublicKeyProvider interface {
+	// PublicKeys returns the public keys, which are published as JWKS by proxy
+	PublicKeys() []jwk.Key
+}
+
 func New(name string) (Plugin, bool) {
 	r := GetRegistration(name)
 	if r == nil {
@@ -38,12 +67,23 @@ func New(name string) (Plugin, bool) {
 	return r.Factory(), true
 }
 
+// Event is the event being delivered
+type Event struct {
+	ID         string
+	Type       string
+	Data       json.RawMessage
+	IngestedAt time.Time
+}
+
 type Context struct {
 	Request    *http.Request
 	ctx        context.Context
 	rw         http.ResponseWriter
 	body       []byte
+	event      *Event
 	terminated bool
+	skipped    bool
+	client     *http.Client
 }
 
 func NewContext(ctx context.Context, r *http.Request, w http.ResponseWriter) *Context {
@@ -62,6 +102,25 @@ func (c *Context) SetRequestBody(body []byte) {
 	c.body = body
 }
 
+// GetEvent returns the event being delivered, it is nil in inbound phase and batch delivery
+func (c *Context) GetEvent() *Event {
+	return c.event
+}
+
+func (c *Context) SetEvent(event *Event) {
+	c.event = event
+}
+
+// HTTPClient returns the client for the requests made by plugin in outbound phase (e.g. requesting a token),
+// it is subject to the same ACL, proxy and TLS settings as deliveries. It is nil if unavailable.
+func (c *Context) HTTPClient() *http.Client {
+	return c.client
+}
+
+func (c *Context) SetHTTPClient(client *http.Client) {
+	c.client = client
+}
+
 func (c *Context) Response(headers map[string]string, code int, body []byte) {
 	response.Response(c.rw, headers, code, body)
 	c.terminated = true
@@ -84,3 +143,12 @@ func (c *Context) WithContext(ctx context.Context) *Context {
 func (c *Context) IsTerminated() bool {
 	return c.terminated
 }
+
+// Skip skips the delivery in outbound phase, the attempt is marked as filtered
+func (c *Context) Skip() {
+	c.skipped = true
+}
+
+func (c *Context) IsSkipped() bool {
+	return c.skipped
+}
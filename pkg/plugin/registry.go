@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 )
 
@@ -13,22 +14,32 @@ const (
 )
 
 type Registration struct {
-	Type    Type
+	Types   []Type
 	Factory func() Plugin
 }
 
+// Supports reports whether the plugin can be applied in phase of typ
+func (r *Registration) Supports(typ Type) bool {
+	return slices.Contains(r.Types, typ)
+}
+
 var mux sync.RWMutex
 var registry = map[string]*Registration{}
 
+// RegisterPlugin registers a plugin of typ, a plugin supports both types when it is registered for each type.
 func RegisterPlugin(typ Type, name string, fn func() Plugin) {
 	mux.Lock()
 	defer mux.Unlock()
-	if _, ok := registry[name]; ok {
-		panic(fmt.Sprintf("plugin '%s' already registered", name))
+	if r, ok := registry[name]; ok {
+		if r.Supports(typ) {
+			panic(fmt.Sprintf("plugin '%s' already registered", name))
+		}
+		r.Types = append(r.Types, typ)
+		return
 	}
 
 	registry[name] = &Registration{
-		Type:    typ,
+		Types:   []Type{typ},
 		Factory: fn,
 	}
 }
@@ -0,0 +1,24 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPlugin(t *testing.T) {
+	factory := func() Plugin { return &MyPlugin{} }
+
+	RegisterPlugin(TypeInbound, "registry-test", factory)
+	r := GetRegistration("registry-test")
+	assert.True(t, r.Supports(TypeInbound))
+	assert.False(t, r.Supports(TypeOutbound))
+
+	RegisterPlugin(TypeOutbound, "registry-test", factory)
+	assert.True(t, r.Supports(TypeInbound))
+	assert.True(t, r.Supports(TypeOutbound))
+
+	assert.PanicsWithValue(t, "plugin 'registry-test' already registered", func() {
+		RegisterPlugin(TypeOutbound, "registry-test", factory)
+	})
+}
@@ -9,6 +9,8 @@ import (
 
 var (
 	ErrReferenceInvalid = errors.New("invalid reference")
+	// ErrReferenceUnresolved is returned by the plugins initialized with a reference that is not resolved
+	ErrReferenceUnresolved = errors.New("unresolved reference")
 )
 
 // Reference represents the definition of a reference.
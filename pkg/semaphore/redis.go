@@ -0,0 +1,53 @@
+package semaphore
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/webhookx-io/webhookx/constants"
+)
+
+var (
+	cacheKey = constants.CacheKey{Name: "semaphore", Version: "v1"}
+
+	// acquireScript evicts the expired permits, then acquires a permit if the limit is not reached.
+	// The permits are stored in a sorted set scored by lease expiry.
+	acquireScript = redis.NewScript(`
+		redis.replicate_commands()
+		local time = redis.call('TIME')
+		local now = time[1] * 1000 + math.floor(time[2] / 1000)
+		local limit = tonumber(ARGV[2])
+		local lease = tonumber(ARGV[3])
+		redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+		if redis.call('ZSCORE', KEYS[1], ARGV[1]) or redis.call('ZCARD', KEYS[1]) < limit then
+			redis.call('ZADD', KEYS[1], now + lease, ARGV[1])
+			if redis.call('PTTL', KEYS[1]) < lease then
+				redis.call('PEXPIRE', KEYS[1], lease)
+			end
+			return 1
+		end
+		return 0
+	`)
+)
+
+// RedisSemaphore is a cluster-wide semaphore with lease expiry
+type RedisSemaphore struct {
+	client *redis.Client
+}
+
+func NewRedisSemaphore(client *redis.Client) *RedisSemaphore {
+	return &RedisSemaphore{client: client}
+}
+
+func (s *RedisSemaphore) Acquire(ctx context.Context, key string, id string, limit int, lease time.Duration) (bool, error) {
+	n, err := acquireScript.Run(ctx, s.client, []string{cacheKey.Build(key)}, id, limit, lease.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (s *RedisSemaphore) Release(ctx context.Context, key string, id string) error {
+	return s.client.ZRem(ctx, cacheKey.Build(key), id).Err()
+}
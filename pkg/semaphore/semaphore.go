@@ -0,0 +1,14 @@
+package semaphore
+
+import (
+	"context"
+	"time"
+)
+
+type Semaphore interface {
+	// Acquire acquires a permit identified by id, returns false if the number of permits reached the limit.
+	// The permit is released automatically once the lease expired.
+	Acquire(ctx context.Context, key string, id string, limit int, lease time.Duration) (bool, error)
+	// Release releases the permit identified by id
+	Release(ctx context.Context, key string, id string) error
+}
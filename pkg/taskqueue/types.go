@@ -5,4 +5,6 @@ type MessageData struct {
 	EndpointId string `json:"endpoint_id"`
 	Attempt    int    `json:"attempt"`
 	Event      string `json:"event"`
+	EventType  string `json:"event_type"`
+	IngestedAt int64  `json:"ingested_at"`
 }
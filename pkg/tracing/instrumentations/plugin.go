@@ -1,6 +1,7 @@
 package instrumentations
 
 import (
+	"github.com/webhookx-io/webhookx/pkg/jwk"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
 )
@@ -26,3 +27,27 @@ func (p *InstrumentedPlugin) ExecuteOutbound(c *plugin.Context) error {
 	defer span.End()
 	return p.Plugin.ExecuteOutbound(c.WithContext(ctx))
 }
+
+func (p *InstrumentedPlugin) HandleOutboundResponse(c *plugin.Context, statusCode int) (bool, error) {
+	handler, ok := p.Plugin.(plugin.OutboundResponseHandler)
+	if !ok {
+		return false, nil
+	}
+	ctx, span := tracing.Start(c.Context(), "plugin."+p.Name()+".outbound_response")
+	defer span.End()
+	return handler.HandleOutboundResponse(c.WithContext(ctx), statusCode)
+}
+
+func (p *InstrumentedPlugin) PublicKeys() []jwk.Key {
+	if provider, ok := p.Plugin.(plugin.PublicKeyProvider); ok {
+		return provider.PublicKeys()
+	}
+	return nil
+}
+
+func (p *InstrumentedPlugin) SignsBody() bool {
+	if signer, ok := p.Plugin.(plugin.Signer); ok {
+		return signer.SignsBody()
+	}
+	return false
+}
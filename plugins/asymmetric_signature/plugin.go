@@ -0,0 +1,271 @@
+package asymmetric_signature
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/jwk"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/secret/reference"
+)
+
+const (
+	FormatJWS = "jws"
+	FormatRaw = "raw"
+
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmPS256 = "PS256"
+
+	minRSAKeySize = 2048
+)
+
+type Key struct {
+	Id         string `json:"id"`
+	PrivateKey string `json:"private_key"`
+}
+
+type Config struct {
+	Keys   []Key  `json:"keys"`
+	Format string `json:"format"`
+}
+
+func (c Config) Schema() *openapi3.Schema {
+	return entities.LookupSchema("AsymmetricSignaturePluginConfiguration")
+}
+
+type signingKey struct {
+	jwk.Key
+	signer crypto.Signer
+}
+
+// AsymmetricSignaturePlugin signs the outbound requests with Ed25519 (EdDSA) or RSA-PSS (PS256) private keys.
+// The first key signs the requests, the public keys of all keys are published as JWKS by proxy,
+// which allows rotating keys by publishing the new key before signing with it.
+type AsymmetricSignaturePlugin struct {
+	plugin.BasePlugin[Config]
+
+	keys []*signingKey
+	ts   time.Time // used in testing
+}
+
+func (p *AsymmetricSignaturePlugin) Name() string {
+	return "asymmetric-signature"
+}
+
+func (p *AsymmetricSignaturePlugin) Priority() int {
+	return -100
+}
+
+func (p *AsymmetricSignaturePlugin) SignsBody() bool {
+	return true
+}
+
+func (p *AsymmetricSignaturePlugin) ValidateConfig(config map[string]interface{}) error {
+	if keys, _ := config["keys"].([]interface{}); len(keys) == 0 {
+		key, err := GenerateKey()
+		if err != nil {
+			return err
+		}
+		config["keys"] = []interface{}{map[string]interface{}{"private_key": key}}
+	}
+
+	if err := p.BasePlugin.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	e := errs.NewValidateError(errors.New("request validation"))
+	keys := config["keys"].([]interface{})
+	fields := make([]interface{}, len(keys))
+	invalid := false
+	for i, v := range keys {
+		key := v.(map[string]interface{})
+		privateKey := key["private_key"].(string)
+		if reference.IsReference(privateKey) {
+			continue
+		}
+		k, err := newSigningKey(Key{PrivateKey: privateKey})
+		if err != nil {
+			fields[i] = map[string]interface{}{"private_key": err.Error()}
+			invalid = true
+			continue
+		}
+		if id, _ := key["id"].(string); id == "" {
+			key["id"] = k.Kid
+		}
+	}
+	if invalid {
+		e.Fields["keys"] = fields
+		return e
+	}
+	return nil
+}
+
+func (p *AsymmetricSignaturePlugin) Init(config map[string]interface{}) error {
+	if err := p.BasePlugin.Init(config); err != nil {
+		return err
+	}
+	p.keys = make([]*signingKey, 0, len(p.Config.Keys))
+	for i, key := range p.Config.Keys {
+		if reference.IsReference(key.PrivateKey) {
+			return fmt.Errorf("keys[%d]: %w: %s", i, reference.ErrReferenceUnresolved, key.PrivateKey)
+		}
+		k, err := newSigningKey(key)
+		if err != nil {
+			return fmt.Errorf("keys[%d]: %w", i, err)
+		}
+		p.keys = append(p.keys, k)
+	}
+	return nil
+}
+
+// PublicKeys returns the public keys of all keys
+func (p *AsymmetricSignaturePlugin) PublicKeys() []jwk.Key {
+	keys := make([]jwk.Key, 0, len(p.keys))
+	for _, k := range p.keys {
+		keys = append(keys, k.Key)
+	}
+	return keys
+}
+
+// GenerateKey generates a PEM encoded Ed25519 private key
+func GenerateKey() (string, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b})), nil
+}
+
+func newSigningKey(key Key) (*signingKey, error) {
+	signer, err := parsePrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	k, err := jwk.New(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	k.Kid = key.Id
+	if k.Kid == "" {
+		k.Kid = k.Thumbprint()
+	}
+	k.Use = "sig"
+	k.Alg = AlgorithmEdDSA
+	if _, ok := signer.(*rsa.PrivateKey); ok {
+		k.Alg = AlgorithmPS256
+	}
+	return &signingKey{Key: k, signer: signer}, nil
+}
+
+// parsePrivateKey parses the PEM encoded private key, either PKCS #8 or PKCS #1 (RSA only)
+func parsePrivateKey(s string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("invalid private key: must be PEM encoded")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("invalid private key: unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return k, nil
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeySize {
+			return nil, fmt.Errorf("invalid private key: RSA key must be at least %d bits", minRSAKeySize)
+		}
+		return k, nil
+	default:
+		return nil, errors.New("invalid private key: must be Ed25519 or RSA key")
+	}
+}
+
+func (k *signingKey) sign(message []byte) ([]byte, error) {
+	if k.Alg == AlgorithmPS256 {
+		digest := sha256.Sum256(message)
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+		return rsa.SignPSS(rand.Reader, k.signer.(*rsa.PrivateKey), crypto.SHA256, digest[:], opts)
+	}
+	return k.signer.Sign(rand.Reader, message, crypto.Hash(0))
+}
+
+// Sign returns the JWS with detached payload (RFC 7515 Appendix F), whose protected header contains alg, kid and iat.
+func (p *AsymmetricSignaturePlugin) Sign(ts time.Time, payload []byte) (string, error) {
+	if len(p.keys) == 0 {
+		return "", errors.New("no signing key")
+	}
+	key := p.keys[0]
+	header, err := json.Marshal(struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		Iat int64  `json:"iat"`
+	}{key.Alg, key.Kid, ts.Unix()})
+	if err != nil {
+		return "", err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(header)
+	signature, err := key.sign([]byte(protected + "." + base64.RawURLEncoding.EncodeToString(payload)))
+	if err != nil {
+		return "", err
+	}
+	return protected + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (p *AsymmetricSignaturePlugin) ExecuteOutbound(c *plugin.Context) error {
+	ts := p.ts
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	if p.Config.Format == FormatRaw {
+		if len(p.keys) == 0 {
+			return errors.New("no signing key")
+		}
+		key := p.keys[0]
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		signature, err := key.sign([]byte(timestamp + "." + string(c.GetRequestBody())))
+		if err != nil {
+			return err
+		}
+		c.Request.Header.Set("webhookx-key-id", key.Kid)
+		c.Request.Header.Set("webhookx-timestamp", timestamp)
+		c.Request.Header.Set("webhookx-asymmetric-signature", base64.RawURLEncoding.EncodeToString(signature))
+		return nil
+	}
+
+	jws, err := p.Sign(ts, c.GetRequestBody())
+	if err != nil {
+		return err
+	}
+	c.Request.Header.Set("webhookx-jws-signature", jws)
+	return nil
+}
@@ -8,8 +8,11 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/plugins/function/sdk"
 	"github.com/webhookx-io/webhookx/utils"
 	"go.uber.org/zap"
@@ -202,6 +205,92 @@ var _ = Describe("JavaScript", Ordered, func() {
 				assert.Nil(GinkgoT(), err)
 				assert.Equal(GinkgoT(), "new body", result.ReturnValue)
 				assert.Equal(GinkgoT(), "new body", string(req.Body))
+				assert.False(GinkgoT(), result.RequestModified)
+			})
+		})
+
+		Context("outbound request", func() {
+			It("setters", func() {
+				script := `function handle() {
+					webhookx.request.setMethod('put')
+					webhookx.request.setURL('https://example.org/hooks?foo=bar')
+					webhookx.request.setHeader('X-Foo', 'bar')
+					webhookx.request.removeHeader('X-Remove')
+					return webhookx.request.getURL()
+				}`
+				function := NewJavaScript(script)
+				r := &http.Request{
+					Method: "POST",
+					URL:    utils.Must(url.Parse("https://example.com")),
+					Header: http.Header{"X-Remove": []string{"value"}},
+				}
+				result, err := function.Execute(&sdk.ExecutionContext{
+					HTTPRequest: &sdk.HTTPRequest{R: r},
+				})
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), "https://example.org/hooks?foo=bar", result.ReturnValue)
+				assert.Equal(GinkgoT(), "PUT", r.Method)
+				assert.Equal(GinkgoT(), "example.org", r.Host)
+				assert.Equal(GinkgoT(), "https://example.org/hooks?foo=bar", r.URL.String())
+				assert.Equal(GinkgoT(), "bar", r.Header.Get("X-Foo"))
+				assert.Empty(GinkgoT(), r.Header.Values("X-Remove"))
+				assert.True(GinkgoT(), result.RequestModified)
+			})
+
+			It("setURL should throw error for invalid url", func() {
+				script := `function handle() { webhookx.request.setURL('/relative') }`
+				function := NewJavaScript(script)
+				_, err := function.Execute(&sdk.ExecutionContext{
+					HTTPRequest: &sdk.HTTPRequest{R: &http.Request{URL: utils.Must(url.Parse("https://example.com"))}},
+				})
+				assert.NotNil(GinkgoT(), err)
+				assert.Contains(GinkgoT(), err.Error(), "invalid url: /relative")
+			})
+		})
+
+		Context("event", func() {
+			It("sanity", func() {
+				script := `function handle() {
+					return {
+						id: webhookx.event.getId(),
+						type: webhookx.event.getType(),
+						data: JSON.parse(webhookx.event.getData()),
+						ingested_at: webhookx.event.getIngestedAt(),
+					}
+				}`
+				function := NewJavaScript(script)
+				result, err := function.Execute(&sdk.ExecutionContext{
+					Event: &entities.Event{
+						ID:         "2q6ItdkHcFz8jQaXxrGp35xsShS",
+						EventType:  "foo.bar",
+						Data:       []byte(`{"key": "value"}`),
+						IngestedAt: types.NewTime(time.UnixMilli(1726285679123)),
+					},
+				})
+				assert.Nil(GinkgoT(), err)
+				v := result.ReturnValue.(map[string]interface{})
+				assert.Equal(GinkgoT(), "2q6ItdkHcFz8jQaXxrGp35xsShS", v["id"])
+				assert.Equal(GinkgoT(), "foo.bar", v["type"])
+				assert.Equal(GinkgoT(), map[string]interface{}{"key": "value"}, v["data"])
+				assert.EqualValues(GinkgoT(), 1726285679123, v["ingested_at"])
+			})
+
+			It("should return null in inbound phase", func() {
+				script := `function handle() { return webhookx.event.getId() }`
+				function := NewJavaScript(script)
+				result, err := function.Execute(&sdk.ExecutionContext{})
+				assert.Nil(GinkgoT(), err)
+				assert.Nil(GinkgoT(), result.ReturnValue)
+			})
+		})
+
+		Context("delivery", func() {
+			It("skip", func() {
+				script := `function handle() { webhookx.delivery.skip() }`
+				function := NewJavaScript(script)
+				result, err := function.Execute(nil)
+				assert.Nil(GinkgoT(), err)
+				assert.True(GinkgoT(), result.Skipped)
 			})
 		})
 
@@ -285,6 +374,17 @@ var _ = Describe("JavaScript", Ordered, func() {
 		})
 	})
 
+	Context("executions", func() {
+		It("executes the function repeatedly", func() {
+			function := NewJavaScript(`var count = 0; function handle() { return ++count }`)
+			for range 2 {
+				result, err := function.Execute(nil)
+				assert.Nil(GinkgoT(), err)
+				assert.EqualValues(GinkgoT(), 1, result.ReturnValue)
+			}
+		})
+	})
+
 	Context("errors", func() {
 		It("error during loading script", func() {
 			script := `throw("js error");`
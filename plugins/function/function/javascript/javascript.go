@@ -4,17 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dop251/goja"
-	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/webhookx-io/webhookx/plugins/function/sdk"
 )
 
+// JavaScript executes the script in a fresh runtime for each execution, so that it can be executed concurrently.
+// The script is compiled once on the first execution.
 type JavaScript struct {
 	opts   Options
-	vm     *goja.Runtime
 	script string
+
+	once    sync.Once
+	program *goja.Program
+	err     error
 }
 
 type Options struct {
@@ -22,19 +27,27 @@ type Options struct {
 }
 
 func New(script string, opts Options) *JavaScript {
-	vm := goja.New()
-	vm.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
 	return &JavaScript{
 		opts:   opts,
 		script: script,
-		vm:     vm,
 	}
 }
 
-var cache, _ = lru.New[string, *goja.Program](128)
+func (m *JavaScript) compile() (*goja.Program, error) {
+	m.once.Do(func() {
+		m.program, m.err = goja.Compile("", m.script, false)
+	})
+	return m.program, m.err
+}
 
 func (m *JavaScript) Execute(ctx *sdk.ExecutionContext) (res sdk.ExecutionResult, err error) {
-	vm := m.vm
+	program, err := m.compile()
+	if err != nil {
+		return res, err
+	}
+
+	vm := goja.New()
+	vm.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
 
 	err = vm.GlobalObject().Set("webhookx", sdk.NewSDK(&sdk.Options{
 		VM:      vm,
@@ -67,15 +80,6 @@ func (m *JavaScript) Execute(ctx *sdk.ExecutionContext) (res sdk.ExecutionResult
 		defer timer.Stop()
 	}
 
-	program, ok := cache.Get(m.script)
-	if !ok {
-		program, err = goja.Compile("", m.script, false)
-		if err != nil {
-			return res, err
-		}
-		cache.Add(m.script, program)
-	}
-
 	_, err = vm.RunProgram(program)
 	if err != nil {
 		if e, ok := err.(*goja.InterruptedError); ok {
@@ -1,9 +1,12 @@
 package function
 
 import (
+	"errors"
+
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/plugins/function/function"
 	"github.com/webhookx-io/webhookx/plugins/function/sdk"
 )
@@ -18,6 +21,8 @@ func (c Config) Schema() *openapi3.Schema {
 
 type FunctionPlugin struct {
 	plugin.BasePlugin[Config]
+
+	fn function.Function
 }
 
 func (p *FunctionPlugin) Name() string {
@@ -28,19 +33,31 @@ func (p *FunctionPlugin) Priority() int {
 	return 80
 }
 
-func (p *FunctionPlugin) ExecuteInbound(c *plugin.Context) error {
-	fn := function.New("javascript", p.Config.Function)
+func (p *FunctionPlugin) Init(config map[string]interface{}) error {
+	if err := p.BasePlugin.Init(config); err != nil {
+		return err
+	}
+	p.fn = function.New("javascript", p.Config.Function)
+	return nil
+}
 
+func (p *FunctionPlugin) ExecuteInbound(c *plugin.Context) error {
 	req := sdk.HTTPRequest{
 		R:    c.Request,
 		Body: c.GetRequestBody(),
 	}
-	res, err := fn.Execute(&sdk.ExecutionContext{
+	res, err := p.fn.Execute(&sdk.ExecutionContext{
 		HTTPRequest: &req,
 	})
 	if err != nil {
 		return err
 	}
+	if res.Skipped {
+		return errors.New("webhookx.delivery.skip() is not available in inbound phase")
+	}
+	if res.RequestModified {
+		return errors.New("webhookx.request.setURL(), setMethod(), setHeader() and removeHeader() are not available in inbound phase")
+	}
 
 	c.SetRequestBody(req.Body)
 	if res.HTTPResponse != nil {
@@ -49,3 +66,35 @@ func (p *FunctionPlugin) ExecuteInbound(c *plugin.Context) error {
 
 	return nil
 }
+
+func (p *FunctionPlugin) ExecuteOutbound(c *plugin.Context) error {
+	req := sdk.HTTPRequest{
+		R:    c.Request,
+		Body: c.GetRequestBody(),
+	}
+	ctx := &sdk.ExecutionContext{
+		HTTPRequest: &req,
+	}
+	if event := c.GetEvent(); event != nil {
+		ctx.Event = &entities.Event{
+			ID:         event.ID,
+			EventType:  event.Type,
+			Data:       event.Data,
+			IngestedAt: types.NewTime(event.IngestedAt),
+		}
+	}
+	res, err := p.fn.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	if res.HTTPResponse != nil {
+		return errors.New("webhookx.response.exit() is not available in outbound phase")
+	}
+
+	c.SetRequestBody(req.Body)
+	if res.Skipped {
+		c.Skip()
+	}
+
+	return nil
+}
@@ -12,6 +12,8 @@ type SDK struct {
 	Response *ResponseSDK `json:"response"`
 	Utils    *UtilsSDK    `json:"utils"`
 	Log      *LogSDK      `json:"log"`
+	Event    *EventSDK    `json:"event"`
+	Delivery *DeliverySDK `json:"delivery"`
 
 	opts *Options
 }
@@ -28,6 +30,8 @@ func NewSDK(opts *Options) *SDK {
 		Utils:    NewUtilsSDK(),
 		Log:      NewLogSDK(),
 		Response: NewResponseSDK(opts),
+		Event:    NewEventSDK(opts),
+		Delivery: NewDeliverySDK(opts),
 		opts:     opts,
 	}
 }
@@ -54,4 +58,9 @@ type ExecutionContext struct {
 type ExecutionResult struct {
 	ReturnValue  interface{}
 	HTTPResponse *HTTPResponse
+	// Skipped reports whether the delivery is skipped
+	Skipped bool
+	// RequestModified reports whether the url, method or headers of request are modified,
+	// which is only available in outbound phase
+	RequestModified bool
 }
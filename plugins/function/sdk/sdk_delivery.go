@@ -0,0 +1,17 @@
+package sdk
+
+type DeliverySDK struct {
+	opts *Options
+}
+
+func NewDeliverySDK(opts *Options) *DeliverySDK {
+	return &DeliverySDK{
+		opts: opts,
+	}
+}
+
+// Skip skips the delivery of event to the endpoint, the attempt is marked as filtered.
+// It is only available in outbound phase.
+func (sdk *DeliverySDK) Skip() {
+	sdk.opts.Result.Skipped = true
+}
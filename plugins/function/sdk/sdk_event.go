@@ -0,0 +1,39 @@
+package sdk
+
+import (
+	"github.com/dop251/goja"
+)
+
+// EventSDK provides the metadata of the event being delivered, which is only available in outbound phase
+type EventSDK struct {
+	opts *Options
+}
+
+func NewEventSDK(opts *Options) *EventSDK {
+	return &EventSDK{
+		opts: opts,
+	}
+}
+
+func (sdk *EventSDK) value(fn func() interface{}) goja.Value {
+	if sdk.opts.Context == nil || sdk.opts.Context.Event == nil {
+		return goja.Null()
+	}
+	return sdk.opts.VM.ToValue(fn())
+}
+
+func (sdk *EventSDK) GetId(call goja.FunctionCall) goja.Value {
+	return sdk.value(func() interface{} { return sdk.opts.Context.Event.ID })
+}
+
+func (sdk *EventSDK) GetType(call goja.FunctionCall) goja.Value {
+	return sdk.value(func() interface{} { return sdk.opts.Context.Event.EventType })
+}
+
+func (sdk *EventSDK) GetData(call goja.FunctionCall) goja.Value {
+	return sdk.value(func() interface{} { return string(sdk.opts.Context.Event.Data) })
+}
+
+func (sdk *EventSDK) GetIngestedAt(call goja.FunctionCall) goja.Value {
+	return sdk.value(func() interface{} { return sdk.opts.Context.Event.IngestedAt.UnixMilli() })
+}
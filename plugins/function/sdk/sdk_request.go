@@ -1,6 +1,10 @@
 package sdk
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/dop251/goja"
 	"github.com/webhookx-io/webhookx/utils"
 )
@@ -56,3 +60,38 @@ func (sdk *RequestSDK) GetBody() string {
 func (sdk *RequestSDK) SetBody(body string) {
 	sdk.opts.Context.HTTPRequest.Body = []byte(body)
 }
+
+func (sdk *RequestSDK) GetURL() string {
+	return sdk.opts.Context.HTTPRequest.R.URL.String()
+}
+
+// SetURL sets the request URL, the setters of request are only available in outbound phase
+func (sdk *RequestSDK) SetURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() {
+		return fmt.Errorf("invalid url: %s", rawURL)
+	}
+	r := sdk.opts.Context.HTTPRequest.R
+	r.URL = u
+	r.Host = u.Host
+	sdk.opts.Result.RequestModified = true
+	return nil
+}
+
+func (sdk *RequestSDK) SetMethod(method string) {
+	sdk.opts.Context.HTTPRequest.R.Method = strings.ToUpper(method)
+	sdk.opts.Result.RequestModified = true
+}
+
+func (sdk *RequestSDK) SetHeader(name string, value string) {
+	sdk.opts.Context.HTTPRequest.R.Header.Set(name, value)
+	sdk.opts.Result.RequestModified = true
+}
+
+func (sdk *RequestSDK) RemoveHeader(name string) {
+	sdk.opts.Context.HTTPRequest.R.Header.Del(name)
+	sdk.opts.Result.RequestModified = true
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/jwk"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/secret"
 	"github.com/webhookx-io/webhookx/pkg/secret/reference"
@@ -45,6 +46,7 @@ type Iterator struct {
 
 	sm      *secret.SecretManager
 	indexes map[string][]plugin.Plugin
+	plugins []plugin.Plugin
 }
 
 func NewIterator(version string) *Iterator {
@@ -89,6 +91,7 @@ func (it *Iterator) LoadPlugins(plugins []*entities.Plugin) error {
 		if tracing.Enabled("plugin") {
 			p = instrumentations.NewInstrumentedPlugin(p)
 		}
+		it.plugins = append(it.plugins, p)
 		if plugin.SourceId != nil {
 			index := it.index(PhaseInbound, *plugin.SourceId)
 			indexes[index] = append(indexes[index], p)
@@ -185,3 +188,23 @@ func (it *Iterator) Iterate(ctx context.Context, phase Phase, id string) iter.Se
 		}
 	}
 }
+
+// PublicKeys returns the public keys published by the loaded plugins, the duplicate keys are removed
+func (it *Iterator) PublicKeys() []jwk.Key {
+	keys := make([]jwk.Key, 0)
+	seen := make(map[string]bool)
+	for _, p := range it.plugins {
+		provider, ok := p.(plugin.PublicKeyProvider)
+		if !ok {
+			continue
+		}
+		for _, key := range provider.PublicKeys() {
+			if seen[key.Kid] {
+				continue
+			}
+			seen[key.Kid] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,194 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/mcache"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
+const (
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+
+	// expiryMargin is how long before expiry a token is refreshed
+	expiryMargin = time.Second * 30
+	tokenTimeout = time.Second * 10
+)
+
+var cacheKey = constants.CacheKey{Name: "oauth2_token", Version: "v1"}
+
+type Config struct {
+	TokenURL     string   `json:"token_url"`
+	ClientId     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+	Audience     string   `json:"audience"`
+	AuthMethod   string   `json:"auth_method"`
+}
+
+func (c Config) Schema() *openapi3.Schema {
+	return entities.LookupSchema("OAuth2PluginConfiguration")
+}
+
+// Token is the access token issued by the authorization server
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresAt is the unix time when token expires, zero means unknown
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// OAuth2Plugin authenticates the outbound requests with the access token obtained by
+// OAuth 2.0 client credentials grant (RFC 6749 section 4.4).
+type OAuth2Plugin struct {
+	plugin.BasePlugin[Config]
+}
+
+func (p *OAuth2Plugin) Name() string {
+	return "oauth2"
+}
+
+func (p *OAuth2Plugin) Priority() int {
+	return -80
+}
+
+func (p *OAuth2Plugin) ExecuteOutbound(c *plugin.Context) error {
+	token, err := p.getToken(c)
+	if err != nil {
+		return err
+	}
+	c.Request.Header.Set("Authorization", authorization(token))
+	return nil
+}
+
+// HandleOutboundResponse refreshes the token and asks to deliver the request again when the endpoint responded 401
+func (p *OAuth2Plugin) HandleOutboundResponse(c *plugin.Context, statusCode int) (bool, error) {
+	if statusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+	if err := mcache.Invalidate(c.Context(), p.cacheKey()); err != nil {
+		return false, err
+	}
+	token, err := p.getToken(c)
+	if err != nil {
+		return false, err
+	}
+	c.Request.Header.Set("Authorization", authorization(token))
+	return true, nil
+}
+
+func authorization(token *Token) string {
+	tokenType := token.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	return tokenType + " " + token.AccessToken
+}
+
+// cacheKey returns the cache key of token, the plugins of identical configuration share the token
+func (p *OAuth2Plugin) cacheKey() string {
+	h := sha256.New()
+	for _, v := range []string{p.Config.TokenURL, p.Config.ClientId, p.Config.ClientSecret,
+		strings.Join(p.Config.Scopes, " "), p.Config.Audience, p.Config.AuthMethod} {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return cacheKey.Build(hex.EncodeToString(h.Sum(nil)))
+}
+
+func (p *OAuth2Plugin) getToken(c *plugin.Context) (*Token, error) {
+	client := c.HTTPClient()
+	if client == nil {
+		return nil, errors.New("http client is unavailable")
+	}
+	opts := &mcache.LoadOptions{
+		TTL: func(value any) time.Duration {
+			token := value.(*Token)
+			if token.ExpiresAt == 0 {
+				return mcache.DefaultL2TTL
+			}
+			return time.Until(time.Unix(token.ExpiresAt, 0)) - expiryMargin
+		},
+	}
+	return mcache.Load(c.Context(), p.cacheKey(), opts, func(ctx context.Context, _ string) (*Token, error) {
+		return p.fetchToken(ctx, client)
+	}, "")
+}
+
+// fetchToken requests a token from the token endpoint
+func (p *OAuth2Plugin) fetchToken(ctx context.Context, client *http.Client) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(p.Config.Scopes) > 0 {
+		form.Set("scope", strings.Join(p.Config.Scopes, " "))
+	}
+	if p.Config.Audience != "" {
+		form.Set("audience", p.Config.Audience)
+	}
+	if p.Config.AuthMethod == AuthMethodClientSecretPost {
+		form.Set("client_id", p.Config.ClientId)
+		form.Set("client_secret", p.Config.ClientSecret)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tokenTimeout)
+	defer cancel()
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Accept", "application/json")
+	if p.Config.AuthMethod != AuthMethodClientSecretPost {
+		r.SetBasicAuth(url.QueryEscape(p.Config.ClientId), url.QueryEscape(p.Config.ClientSecret))
+	}
+
+	resp, err := client.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// the response body is not included, it may contain credentials
+		return nil, fmt.Errorf("failed to request token: unexpected status code %d", resp.StatusCode)
+	}
+
+	var res struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if res.AccessToken == "" {
+		return nil, fmt.Errorf("failed to request token: access_token is missing")
+	}
+
+	token := &Token{
+		AccessToken: res.AccessToken,
+		TokenType:   res.TokenType,
+	}
+	if res.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second).Unix()
+	}
+	return token, nil
+}
@@ -0,0 +1,170 @@
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/mcache"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
+type memoryCache struct {
+	data map[string][]byte
+}
+
+func (m *memoryCache) Put(ctx context.Context, key string, val interface{}, expiration time.Duration) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	m.data[key] = b
+	return nil
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string, val interface{}) (bool, error) {
+	b, ok := m.data[key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(b, val)
+}
+
+func (m *memoryCache) Remove(ctx context.Context, key string) error {
+	delete(m.data, key)
+	return nil
+}
+
+func (m *memoryCache) Exist(ctx context.Context, key string) (bool, error) {
+	_, ok := m.data[key]
+	return ok, nil
+}
+
+func TestOAuth2Plugin(t *testing.T) {
+	mcache.Set(mcache.NewMCache(&mcache.Options{
+		L1Size: 100,
+		L1TTL:  time.Second,
+		L2:     &memoryCache{data: make(map[string][]byte)},
+	}))
+
+	var requests atomic.Int32
+	var lastForm atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		_ = r.ParseForm()
+		form := r.PostForm
+		if id, secret, ok := r.BasicAuth(); ok {
+			form.Set("basic", id+":"+secret)
+		}
+		lastForm.Store(form)
+		if form.Get("client_id") == "invalid" {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600}`, n)
+	}))
+	defer server.Close()
+
+	newContext := func() *plugin.Context {
+		r, err := http.NewRequest("POST", "https://example.com", nil)
+		assert.NoError(t, err)
+		c := plugin.NewContext(context.TODO(), r, nil)
+		c.SetHTTPClient(server.Client())
+		return c
+	}
+
+	t.Run("should set token and cache it", func(t *testing.T) {
+		requests.Store(0)
+		p := new(OAuth2Plugin)
+		p.Config = Config{
+			TokenURL:     server.URL,
+			ClientId:     "client",
+			ClientSecret: "secret",
+			Scopes:       []string{"a", "b"},
+			Audience:     "https://api.example.com",
+			AuthMethod:   AuthMethodClientSecretBasic,
+		}
+
+		c := newContext()
+		assert.NoError(t, p.ExecuteOutbound(c))
+		assert.Equal(t, "Bearer token-1", c.Request.Header.Get("Authorization"))
+
+		form := lastForm.Load().(url.Values)
+		assert.Equal(t, []string{"client_credentials"}, form["grant_type"])
+		assert.Equal(t, []string{"a b"}, form["scope"])
+		assert.Equal(t, []string{"https://api.example.com"}, form["audience"])
+		assert.Equal(t, []string{"client:secret"}, form["basic"])
+		assert.Empty(t, form["client_secret"])
+
+		c = newContext()
+		assert.NoError(t, p.ExecuteOutbound(c))
+		assert.Equal(t, "Bearer token-1", c.Request.Header.Get("Authorization"))
+		assert.EqualValues(t, 1, requests.Load())
+	})
+
+	t.Run("should refresh token on 401", func(t *testing.T) {
+		requests.Store(0)
+		p := new(OAuth2Plugin)
+		p.Config = Config{
+			TokenURL:     server.URL,
+			ClientId:     "client2",
+			ClientSecret: "secret",
+			AuthMethod:   AuthMethodClientSecretPost,
+		}
+
+		c := newContext()
+		assert.NoError(t, p.ExecuteOutbound(c))
+		assert.Equal(t, "Bearer token-1", c.Request.Header.Get("Authorization"))
+		form := lastForm.Load().(url.Values)
+		assert.Equal(t, []string{"client2"}, form["client_id"])
+		assert.Equal(t, []string{"secret"}, form["client_secret"])
+
+		resend, err := p.HandleOutboundResponse(c, 200)
+		assert.NoError(t, err)
+		assert.False(t, resend)
+
+		resend, err = p.HandleOutboundResponse(c, 401)
+		assert.NoError(t, err)
+		assert.True(t, resend)
+		assert.Equal(t, "Bearer token-2", c.Request.Header.Get("Authorization"))
+
+		// the refreshed token is cached
+		c = newContext()
+		assert.NoError(t, p.ExecuteOutbound(c))
+		assert.Equal(t, "Bearer token-2", c.Request.Header.Get("Authorization"))
+		assert.EqualValues(t, 2, requests.Load())
+	})
+
+	t.Run("should return error when token request failed", func(t *testing.T) {
+		p := new(OAuth2Plugin)
+		p.Config = Config{
+			TokenURL:     server.URL,
+			ClientId:     "invalid",
+			ClientSecret: "secret",
+			AuthMethod:   AuthMethodClientSecretPost,
+		}
+		err := p.ExecuteOutbound(newContext())
+		assert.EqualError(t, err, `failed to request token: unexpected status code 400`)
+	})
+
+	t.Run("should return error when http client is unavailable", func(t *testing.T) {
+		p := new(OAuth2Plugin)
+		p.Config = Config{
+			TokenURL:     server.URL,
+			ClientId:     "client",
+			ClientSecret: "unavailable",
+		}
+		c := newContext()
+		c.SetHTTPClient(nil)
+		assert.EqualError(t, p.ExecuteOutbound(c), "http client is unavailable")
+	})
+}
@@ -2,12 +2,16 @@ package plugins
 
 import (
 	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/plugins/asymmetric_signature"
 	basic_auth "github.com/webhookx-io/webhookx/plugins/basic-auth"
 	integration_auth "github.com/webhookx-io/webhookx/plugins/connect-auth"
 	"github.com/webhookx-io/webhookx/plugins/event-validation"
 	"github.com/webhookx-io/webhookx/plugins/function"
 	hmac_auth "github.com/webhookx-io/webhookx/plugins/hmac-auth"
 	key_auth "github.com/webhookx-io/webhookx/plugins/key-auth"
+	"github.com/webhookx-io/webhookx/plugins/oauth2"
+	"github.com/webhookx-io/webhookx/plugins/standard_webhooks"
+	"github.com/webhookx-io/webhookx/plugins/transform"
 	"github.com/webhookx-io/webhookx/plugins/wasm"
 	"github.com/webhookx-io/webhookx/plugins/webhookx_signature"
 )
@@ -16,12 +20,27 @@ func LoadPlugins() {
 	plugin.RegisterPlugin(plugin.TypeInbound, "function", func() plugin.Plugin {
 		return &function.FunctionPlugin{}
 	})
+	plugin.RegisterPlugin(plugin.TypeOutbound, "function", func() plugin.Plugin {
+		return &function.FunctionPlugin{}
+	})
 	plugin.RegisterPlugin(plugin.TypeOutbound, "wasm", func() plugin.Plugin {
 		return &wasm.WasmPlugin{}
 	})
 	plugin.RegisterPlugin(plugin.TypeOutbound, "webhookx-signature", func() plugin.Plugin {
 		return &webhookx_signature.SignaturePlugin{}
 	})
+	plugin.RegisterPlugin(plugin.TypeOutbound, "standard-webhooks", func() plugin.Plugin {
+		return &standard_webhooks.StandardWebhooksPlugin{}
+	})
+	plugin.RegisterPlugin(plugin.TypeOutbound, "asymmetric-signature", func() plugin.Plugin {
+		return &asymmetric_signature.AsymmetricSignaturePlugin{}
+	})
+	plugin.RegisterPlugin(plugin.TypeOutbound, "oauth2", func() plugin.Plugin {
+		return &oauth2.OAuth2Plugin{}
+	})
+	plugin.RegisterPlugin(plugin.TypeOutbound, "transform", func() plugin.Plugin {
+		return &transform.TransformPlugin{}
+	})
 	plugin.RegisterPlugin(plugin.TypeInbound, "event-validation", func() plugin.Plugin {
 		return &event_validation.EventValidationPlugin{}
 	})
@@ -0,0 +1,175 @@
+package standard_webhooks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/secret/reference"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+const (
+	SecretPrefix     = "whsec_"
+	PrivateKeyPrefix = "whsk_"
+)
+
+type Config struct {
+	Secrets     []string `json:"secrets"`
+	PrivateKeys []string `json:"private_keys"`
+}
+
+func (c Config) Schema() *openapi3.Schema {
+	return entities.LookupSchema("StandardWebhooksPluginConfiguration")
+}
+
+// StandardWebhooksPlugin signs the outbound requests according to the Standard Webhooks specification
+// (https://www.standardwebhooks.com). Every secret and private key produces a signature, which allows
+// rotating them without downtime.
+type StandardWebhooksPlugin struct {
+	plugin.BasePlugin[Config]
+
+	ts time.Time // used in testing
+}
+
+func (p *StandardWebhooksPlugin) Name() string {
+	return "standard-webhooks"
+}
+
+func (p *StandardWebhooksPlugin) Priority() int {
+	return -100
+}
+
+func (p *StandardWebhooksPlugin) SignsBody() bool {
+	return true
+}
+
+func (p *StandardWebhooksPlugin) ValidateConfig(config map[string]interface{}) error {
+	secrets, _ := config["secrets"].([]interface{})
+	privateKeys, _ := config["private_keys"].([]interface{})
+	if len(secrets) == 0 && len(privateKeys) == 0 {
+		config["secrets"] = []interface{}{GenerateSecret()}
+	}
+
+	if err := p.BasePlugin.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	e := errs.NewValidateError(errors.New("request validation"))
+	validate := func(name string, values []interface{}, parse func(string) error) {
+		fields := make([]interface{}, len(values))
+		invalid := false
+		for i, v := range values {
+			s, _ := v.(string)
+			if reference.IsReference(s) {
+				continue
+			}
+			if err := parse(s); err != nil {
+				fields[i] = err.Error()
+				invalid = true
+			}
+		}
+		if invalid {
+			e.Fields[name] = fields
+		}
+	}
+	validate("secrets", secrets, func(s string) error {
+		_, err := parseSecret(s)
+		return err
+	})
+	validate("private_keys", privateKeys, func(s string) error {
+		_, err := parsePrivateKey(s)
+		return err
+	})
+	if len(e.Fields) > 0 {
+		return e
+	}
+	return nil
+}
+
+// GenerateSecret generates a random secret
+func GenerateSecret() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return SecretPrefix + base64.StdEncoding.EncodeToString(b)
+}
+
+func parseSecret(secret string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, SecretPrefix))
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid secret: must be base64 encoded")
+	}
+	return b, nil
+}
+
+// parsePrivateKey parses the base64 encoded ed25519 private key, either the 32 bytes seed or the 64 bytes key
+func parsePrivateKey(key string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key, PrivateKeyPrefix))
+	if err != nil {
+		return nil, errors.New("invalid private key: must be base64 encoded")
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, fmt.Errorf("invalid private key: must be %d or %d bytes", ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
+
+// Sign returns the space delimited signatures of message
+func (p *StandardWebhooksPlugin) Sign(id string, ts time.Time, payload []byte) (string, error) {
+	message := []byte(id + "." + strconv.FormatInt(ts.Unix(), 10) + "." + string(payload))
+
+	signatures := make([]string, 0, len(p.Config.Secrets)+len(p.Config.PrivateKeys))
+	for _, s := range p.Config.Secrets {
+		secret, err := parseSecret(s)
+		if err != nil {
+			return "", err
+		}
+		signatures = append(signatures, "v1,"+utils.HmacEncode("sha-256", secret, message, "base64"))
+	}
+	for _, k := range p.Config.PrivateKeys {
+		key, err := parsePrivateKey(k)
+		if err != nil {
+			return "", err
+		}
+		signatures = append(signatures, "v1a,"+base64.StdEncoding.EncodeToString(ed25519.Sign(key, message)))
+	}
+	return strings.Join(signatures, " "), nil
+}
+
+func (p *StandardWebhooksPlugin) ExecuteOutbound(c *plugin.Context) error {
+	ts := p.ts
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	// the id is the same when the event is redelivered
+	id := c.Request.Header.Get("Webhookx-Event-Id")
+	if id == "" {
+		id = c.Request.Header.Get("Webhookx-Batch-Id")
+	}
+	if id == "" {
+		id = utils.KSUID()
+	}
+
+	signature, err := p.Sign(id, ts, c.GetRequestBody())
+	if err != nil {
+		return err
+	}
+	c.Request.Header.Set("webhook-id", id)
+	c.Request.Header.Set("webhook-timestamp", strconv.FormatInt(ts.Unix(), 10))
+	c.Request.Header.Set("webhook-signature", signature)
+	return nil
+}
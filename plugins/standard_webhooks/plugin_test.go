@@ -0,0 +1,97 @@
+package standard_webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/openapi"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/plugins/connect-auth/verifier"
+)
+
+const (
+	testSecret  = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	testId      = "msg_p5jXN8AQM9LWM0D4loKWxJek"
+	testPayload = `{"test": 2432232314}`
+)
+
+func TestSign(t *testing.T) {
+	p := new(StandardWebhooksPlugin)
+	p.Config.Secrets = []string{testSecret}
+
+	signature, err := p.Sign(testId, time.Unix(1614265330, 0), []byte(testPayload))
+	assert.NoError(t, err)
+	assert.Equal(t, "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", signature)
+}
+
+func TestExecute(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, ed25519.SeedSize)
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	p := new(StandardWebhooksPlugin)
+	p.ts = time.Now()
+	p.Config.Secrets = []string{GenerateSecret(), testSecret}
+	p.Config.PrivateKeys = []string{PrivateKeyPrefix + base64.StdEncoding.EncodeToString(seed)}
+
+	r, err := http.NewRequest("POST", "https://example.com", nil)
+	assert.NoError(t, err)
+	r.Header.Set("Webhookx-Event-Id", testId)
+	c := plugin.NewContext(context.TODO(), r, nil)
+	c.SetRequestBody([]byte(testPayload))
+	assert.NoError(t, p.ExecuteOutbound(c))
+
+	assert.Equal(t, testId, r.Header.Get("webhook-id"))
+	signatures := strings.Split(r.Header.Get("webhook-signature"), " ")
+	assert.Len(t, signatures, 3)
+	assert.True(t, strings.HasPrefix(signatures[0], "v1,"))
+	assert.True(t, strings.HasPrefix(signatures[1], "v1,"))
+	assert.True(t, strings.HasPrefix(signatures[2], "v1a,"))
+
+	// the signature of rotated secret is verifiable
+	v := verifier.NewStandardWebhooksVerifier()
+	req := r.Clone(context.TODO())
+	req.Body = io.NopCloser(strings.NewReader(testPayload))
+	res, err := v.Verify(context.TODO(), &verifier.Request{R: req}, map[string]interface{}{
+		"secret":           testSecret,
+		"tolerance_window": 300,
+	})
+	assert.NoError(t, err)
+	assert.True(t, res.Verified)
+
+	// the asymmetric signature is verifiable with the public key
+	message := testId + "." + r.Header.Get("webhook-timestamp") + "." + testPayload
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(signatures[2], "v1a,"))
+	assert.NoError(t, err)
+	assert.True(t, ed25519.Verify(privateKey.Public().(ed25519.PublicKey), []byte(message), sig))
+}
+
+func TestValidateConfig(t *testing.T) {
+	openapi.LoadOpenAPI(webhookx.OpenAPI)
+	p := new(StandardWebhooksPlugin)
+
+	config := map[string]interface{}{}
+	assert.NoError(t, p.ValidateConfig(config))
+	secrets := config["secrets"].([]interface{})
+	assert.Len(t, secrets, 1)
+	assert.True(t, strings.HasPrefix(secrets[0].(string), SecretPrefix))
+
+	config = map[string]interface{}{
+		"secrets":      []interface{}{testSecret, "{secret://vault/webhookx/secret}", "whsec_!"},
+		"private_keys": []interface{}{"whsk_" + base64.StdEncoding.EncodeToString([]byte("short"))},
+	}
+	err := p.ValidateConfig(config)
+	assert.Error(t, err)
+	b, _ := json.Marshal(err.(*errs.ValidateError).Fields)
+	assert.Equal(t, `{"private_keys":["invalid private key: must be 32 or 64 bytes"],"secrets":[null,null,"invalid secret: must be base64 encoded"]}`, string(b))
+}
@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var funcs = template.FuncMap{
+	"json":    toJSON,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"replace": replace,
+	"join":    join,
+	"default": defaultValue,
+	"date":    date,
+}
+
+// toJSON returns the JSON encoding of v, e.g. {{ json .data }} or {{ json .type }} for a quoted string
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// replace replaces all old with replacement in s, e.g. {{ .type | replace "." "_" }}
+func replace(old, replacement string, s string) string {
+	return strings.ReplaceAll(s, old, replacement)
+}
+
+// join concatenates the elements of list with sep, e.g. {{ join ", " .data.tags }}
+func join(sep string, list []interface{}) string {
+	elems := make([]string, len(list))
+	for i, v := range list {
+		elems[i] = fmt.Sprint(v)
+	}
+	return strings.Join(elems, sep)
+}
+
+// defaultValue returns def when v is absent or empty, e.g. {{ .data.name | default "unknown" }}
+func defaultValue(def interface{}, v interface{}) interface{} {
+	if v == nil || v == "" {
+		return def
+	}
+	return v
+}
+
+// date formats the unix timestamp in milliseconds with layout in UTC, e.g. {{ date "2006-01-02T15:04:05Z07:00" .ingested_at }}
+func date(layout string, ms interface{}) (string, error) {
+	var n int64
+	switch v := ms.(type) {
+	case int64:
+		n = v
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return "", err
+		}
+		n = i
+	default:
+		return "", fmt.Errorf("invalid timestamp: %v", ms)
+	}
+	return time.UnixMilli(n).UTC().Format(layout), nil
+}
@@ -0,0 +1,230 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
+type Config struct {
+	Body    string            `json:"body"`
+	Headers map[string]string `json:"headers"`
+	Path    string            `json:"path"`
+	Query   map[string]string `json:"query"`
+}
+
+func (c Config) Schema() *openapi3.Schema {
+	return entities.LookupSchema("TransformPluginConfiguration")
+}
+
+// TransformPlugin transforms the outbound requests with Go templates (text/template).
+// The templates are compiled once when the plugin is initialized.
+type TransformPlugin struct {
+	plugin.BasePlugin[Config]
+
+	body    *template.Template
+	headers map[string]*template.Template
+	path    *template.Template
+	query   map[string]*template.Template
+}
+
+func (p *TransformPlugin) Name() string {
+	return "transform"
+}
+
+func (p *TransformPlugin) Priority() int {
+	return -60
+}
+
+func (p *TransformPlugin) ValidateConfig(config map[string]interface{}) error {
+	if err := p.BasePlugin.ValidateConfig(config); err != nil {
+		return err
+	}
+	return new(TransformPlugin).Init(config)
+}
+
+func (p *TransformPlugin) Init(config map[string]interface{}) error {
+	if err := p.BasePlugin.Init(config); err != nil {
+		return err
+	}
+
+	e := errs.NewValidateError(errors.New("request validation"))
+	compile := func(name string, text string, fields map[string]interface{}, key string) *template.Template {
+		tmpl, err := template.New(name).Funcs(funcs).Funcs(template.FuncMap{emptyIfNil: emptyIfNilValue}).
+			Option("missingkey=zero").Parse(text)
+		if err != nil {
+			fields[key] = err.Error()
+			return nil
+		}
+		for _, t := range tmpl.Templates() {
+			rewrite(t.Tree, t.Root)
+		}
+		return tmpl
+	}
+	compileMap := func(name string, texts map[string]string) map[string]*template.Template {
+		templates := make(map[string]*template.Template, len(texts))
+		fields := make(map[string]interface{})
+		for k, text := range texts {
+			templates[k] = compile(name+"."+k, text, fields, k)
+		}
+		if len(fields) > 0 {
+			e.Fields[name] = fields
+		}
+		return templates
+	}
+
+	p.body, p.path = nil, nil
+	if p.Config.Body != "" {
+		p.body = compile("body", p.Config.Body, e.Fields, "body")
+	}
+	if p.Config.Path != "" {
+		p.path = compile("path", p.Config.Path, e.Fields, "path")
+	}
+	p.headers = compileMap("headers", p.Config.Headers)
+	p.query = compileMap("query", p.Config.Query)
+	if len(e.Fields) > 0 {
+		return e
+	}
+	return nil
+}
+
+// emptyIfNil is the function appended to the actions of templates, the absent fields and nulls of
+// data are nil which are rendered as "<no value>" by text/template, it turns them into empty strings.
+const emptyIfNil = "__emptyIfNil"
+
+func emptyIfNilValue(v interface{}) interface{} {
+	if v == nil {
+		return ""
+	}
+	return v
+}
+
+// rewrite appends emptyIfNil to the pipelines of actions that print values, e.g. {{ .data.name }}
+// becomes {{ .data.name | __emptyIfNil }}
+func rewrite(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			rewrite(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		identifier := parse.NewIdentifier(emptyIfNil).SetTree(tree).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{identifier},
+		})
+	case *parse.IfNode:
+		rewrite(tree, n.List)
+		rewrite(tree, n.ElseList)
+	case *parse.RangeNode:
+		rewrite(tree, n.List)
+		rewrite(tree, n.ElseList)
+	case *parse.WithNode:
+		rewrite(tree, n.List)
+		rewrite(tree, n.ElseList)
+	}
+}
+
+// templateData returns the data that templates are executed with
+func templateData(c *plugin.Context) map[string]interface{} {
+	data := map[string]interface{}{
+		"body": decode(c.GetRequestBody()),
+	}
+	if event := c.GetEvent(); event != nil {
+		data["id"] = event.ID
+		data["type"] = event.Type
+		data["data"] = decode(event.Data)
+		data["ingested_at"] = event.IngestedAt.UnixMilli()
+	}
+	return data
+}
+
+// decode decodes JSON, the numbers are kept as json.Number to render them as they are.
+// It returns the string if the value is not a valid JSON.
+func decode(b []byte) interface{} {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return string(b)
+	}
+	return v
+}
+
+func render(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+func (p *TransformPlugin) ExecuteOutbound(c *plugin.Context) error {
+	data := templateData(c)
+
+	for name, tmpl := range p.headers {
+		value, err := render(tmpl, data)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			c.Request.Header.Del(name)
+		} else {
+			c.Request.Header.Set(name, value)
+		}
+	}
+
+	if p.path != nil {
+		path, err := render(p.path, data)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		c.Request.URL.Path = path
+		c.Request.URL.RawPath = ""
+	}
+
+	if len(p.query) > 0 {
+		query := c.Request.URL.Query()
+		for name, tmpl := range p.query {
+			value, err := render(tmpl, data)
+			if err != nil {
+				return err
+			}
+			if value == "" {
+				query.Del(name)
+			} else {
+				query.Set(name, value)
+			}
+		}
+		c.Request.URL.RawQuery = query.Encode()
+	}
+
+	if p.body != nil {
+		body, err := render(p.body, data)
+		if err != nil {
+			return err
+		}
+		c.SetRequestBody([]byte(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/openapi"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
+func newContext(t *testing.T, event *plugin.Event) *plugin.Context {
+	r, err := http.NewRequest("POST", "https://example.com/hooks?token=abc&foo=bar", nil)
+	assert.NoError(t, err)
+	r.Header.Set("Content-Type", "application/json")
+	c := plugin.NewContext(context.TODO(), r, nil)
+	c.SetRequestBody(event.Data)
+	c.SetEvent(event)
+	return c
+}
+
+func TestExecute(t *testing.T) {
+	p := new(TransformPlugin)
+	assert.NoError(t, p.Init(map[string]interface{}{
+		"body": `{"text": {{ json (printf "[%s] %s" .type .data.message) }}, "amount": {{ .data.amount }}, "tags": "{{ join ", " .data.tags }}", "at": "{{ date "2006-01-02" .ingested_at }}"}`,
+		"headers": map[string]interface{}{
+			"X-Event-Type": `{{ .type | upper }}`,
+			"X-Customer":   `{{ .data.customer | default "unknown" }}`,
+			"Content-Type": "",
+		},
+		"path": `/services/{{ .type | replace "." "/" }}`,
+		"query": map[string]interface{}{
+			"id":  "{{ .id }}",
+			"foo": "",
+		},
+	}))
+
+	c := newContext(t, &plugin.Event{
+		ID:         "2q6ItdkHcFz8jQaXxrGp35xsShS",
+		Type:       "charge.succeeded",
+		Data:       json.RawMessage(`{"message": "hello \"world\"", "amount": 10000000, "tags": ["a", "b"]}`),
+		IngestedAt: time.Date(2024, 9, 14, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, p.ExecuteOutbound(c))
+
+	assert.JSONEq(t,
+		`{"text": "[charge.succeeded] hello \"world\"", "amount": 10000000, "tags": "a, b", "at": "2024-09-14"}`,
+		string(c.GetRequestBody()))
+	assert.Equal(t, "CHARGE.SUCCEEDED", c.Request.Header.Get("X-Event-Type"))
+	assert.Equal(t, "unknown", c.Request.Header.Get("X-Customer"))
+	assert.Empty(t, c.Request.Header.Get("Content-Type"))
+	assert.Equal(t, "https://example.com/services/charge/succeeded?id=2q6ItdkHcFz8jQaXxrGp35xsShS&token=abc", c.Request.URL.String())
+}
+
+func TestExecuteMissingKey(t *testing.T) {
+	p := new(TransformPlugin)
+	assert.NoError(t, p.Init(map[string]interface{}{
+		"body": `{"customer": "{{ .data.customer }}", "note": "{{ .data.note }}", "name": "{{ with .data.name }}{{ . }}{{ end }}"}`,
+		"headers": map[string]interface{}{
+			"X-Customer": `{{ .data.customer }}`,
+			"X-Note":     `{{ .data.note }}`,
+		},
+		"query": map[string]interface{}{
+			"token": `{{ .data.token }}`,
+		},
+	}))
+
+	c := newContext(t, &plugin.Event{Data: json.RawMessage(`{"note": null, "name": "foo"}`)})
+	c.Request.Header.Set("X-Customer", "c1")
+	assert.NoError(t, p.ExecuteOutbound(c))
+
+	assert.JSONEq(t, `{"customer": "", "note": "", "name": "foo"}`, string(c.GetRequestBody()))
+	_, ok := c.Request.Header["X-Customer"]
+	assert.False(t, ok)
+	_, ok = c.Request.Header["X-Note"]
+	assert.False(t, ok)
+	assert.Equal(t, "https://example.com/hooks?foo=bar", c.Request.URL.String())
+}
+
+func TestExecuteWithoutEvent(t *testing.T) {
+	p := new(TransformPlugin)
+	assert.NoError(t, p.Init(map[string]interface{}{
+		"body": `{"count": {{ len .body }}, "type": {{ json .type }}}`,
+	}))
+
+	r, err := http.NewRequest("POST", "https://example.com", nil)
+	assert.NoError(t, err)
+	c := plugin.NewContext(context.TODO(), r, nil)
+	c.SetRequestBody([]byte(`[{"id": 1}, {"id": 2}]`))
+	assert.NoError(t, p.ExecuteOutbound(c))
+	assert.Equal(t, `{"count": 2, "type": null}`, string(c.GetRequestBody()))
+}
+
+func TestExecuteError(t *testing.T) {
+	p := new(TransformPlugin)
+	assert.NoError(t, p.Init(map[string]interface{}{
+		"body": `{{ date "2006" .data.at }}`,
+	}))
+	c := newContext(t, &plugin.Event{Data: json.RawMessage(`{"at": "now"}`)})
+	err := p.ExecuteOutbound(c)
+	assert.EqualError(t, err, `failed to render body: template: body:1:3: executing "body" at <date "2006" .data.at>: error calling date: invalid timestamp: now`)
+}
+
+func TestValidateConfig(t *testing.T) {
+	openapi.LoadOpenAPI(webhookx.OpenAPI)
+	p := new(TransformPlugin)
+
+	config := map[string]interface{}{}
+	assert.NoError(t, p.ValidateConfig(config))
+
+	config = map[string]interface{}{
+		"body":    "{{ .data ",
+		"headers": map[string]interface{}{"X-Foo": "{{ unknown }}", "X-Bar": "bar"},
+		"path":    "/{{ .type }}",
+	}
+	err := p.ValidateConfig(config)
+	assert.Error(t, err)
+	b, _ := json.Marshal(err.(*errs.ValidateError).Fields)
+	assert.Equal(t, `{"body":"template: body:1: unclosed action","headers":{"X-Foo":"template: headers.X-Foo:1: function \"unknown\" not defined"}}`, string(b))
+}
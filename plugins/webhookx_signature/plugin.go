@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -14,7 +15,33 @@ import (
 )
 
 type Config struct {
-	SigningSecret string `json:"signing_secret"`
+	SigningSecret  string   `json:"signing_secret"`
+	SigningSecrets []Secret `json:"signing_secrets,omitempty"`
+}
+
+// Secret is an additional signing secret, which is used during rotation
+type Secret struct {
+	Secret string `json:"secret"`
+	// ExpiresAt is the unix time in milliseconds when the secret is retired, nil means never
+	ExpiresAt *int64 `json:"expires_at"`
+}
+
+func (s Secret) Active(now time.Time) bool {
+	return s.ExpiresAt == nil || now.UnixMilli() < *s.ExpiresAt
+}
+
+// ActiveSecrets returns the signing secrets that are not expired, the primary secret comes first
+func (c Config) ActiveSecrets(now time.Time) []string {
+	secrets := make([]string, 0, len(c.SigningSecrets)+1)
+	if c.SigningSecret != "" {
+		secrets = append(secrets, c.SigningSecret)
+	}
+	for _, secret := range c.SigningSecrets {
+		if secret.Active(now) {
+			secrets = append(secrets, secret.Secret)
+		}
+	}
+	return secrets
 }
 
 func (c Config) Schema() *openapi3.Schema {
@@ -35,6 +62,10 @@ func (p *SignaturePlugin) Priority() int {
 	return -100
 }
 
+func (p *SignaturePlugin) SignsBody() bool {
+	return true
+}
+
 // TODO
 func (p *SignaturePlugin) ValidateConfig(config map[string]interface{}) error {
 	if _, ok := config["signing_secret"]; !ok {
@@ -52,13 +83,36 @@ func computeSignature(ts time.Time, payload []byte, secret string) []byte {
 	return mac.Sum(nil)
 }
 
+// RotateSecret generates a new primary secret, the current one keeps signing until it expires after overlap
+func (p *SignaturePlugin) RotateSecret(now time.Time, overlap time.Duration) {
+	secrets := make([]Secret, 0, len(p.Config.SigningSecrets)+1)
+	if p.Config.SigningSecret != "" {
+		secrets = append(secrets, Secret{
+			Secret:    p.Config.SigningSecret,
+			ExpiresAt: new(now.Add(overlap).UnixMilli()),
+		})
+	}
+	for _, secret := range p.Config.SigningSecrets {
+		if secret.Active(now) {
+			secrets = append(secrets, secret)
+		}
+	}
+	p.Config.SigningSecret = utils.RandomString(32)
+	p.Config.SigningSecrets = secrets
+}
+
 func (p *SignaturePlugin) ExecuteOutbound(c *plugin.Context) error {
 	ts := p.ts
 	if ts.IsZero() {
 		ts = time.Now()
 	}
-	signature := computeSignature(ts, c.GetRequestBody(), p.Config.SigningSecret)
-	c.Request.Header.Set("webhookx-signature", "v1="+hex.EncodeToString(signature))
+	secrets := p.Config.ActiveSecrets(ts)
+	signatures := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		signature := computeSignature(ts, c.GetRequestBody(), secret)
+		signatures = append(signatures, "v1="+hex.EncodeToString(signature))
+	}
+	c.Request.Header.Set("webhookx-signature", strings.Join(signatures, ","))
 	c.Request.Header.Set("webhookx-timestamp", strconv.FormatInt(ts.Unix(), 10))
 	return nil
 }
@@ -31,3 +31,49 @@ func TestExecute(t *testing.T) {
 	assert.Equal(t, "v1=e2af2618d5ffd700eb369904b7237ec4ac7d37873cfe6654265af2e53b44da6b", c.Request.Header.Get("webhookx-signature"))
 	assert.Equal(t, "1726285679", c.Request.Header.Get("webhookx-timestamp"))
 }
+
+func TestExecuteWithMultipleSecrets(t *testing.T) {
+	p := new(SignaturePlugin)
+	p.ts = time.Unix(1726285679, 0)
+	p.Config.SigningSecret = "QGvaZ0uPwA9nYi7jr31JtZn1EKK4pJpK"
+	p.Config.SigningSecrets = []Secret{
+		{Secret: "QGvaZ0uPwA9nYi7jr31JtZn1EKK4pJpK", ExpiresAt: new(p.ts.Add(time.Hour).UnixMilli())},
+		{Secret: "expired", ExpiresAt: new(p.ts.Add(-time.Hour).UnixMilli())},
+		{Secret: "QGvaZ0uPwA9nYi7jr31JtZn1EKK4pJpK"},
+	}
+
+	r, err := http.NewRequest("POST", "https://example.com", nil)
+	assert.NoError(t, err)
+
+	c := plugin.NewContext(context.TODO(), r, nil)
+	c.SetRequestBody([]byte("foo"))
+	assert.NoError(t, p.ExecuteOutbound(c))
+	signature := "v1=e2af2618d5ffd700eb369904b7237ec4ac7d37873cfe6654265af2e53b44da6b"
+	assert.Equal(t, signature+","+signature+","+signature, c.Request.Header.Get("webhookx-signature"))
+}
+
+func TestRotateSecret(t *testing.T) {
+	now := time.Now()
+	p := new(SignaturePlugin)
+	p.Config.SigningSecret = "old"
+	p.Config.SigningSecrets = []Secret{
+		{Secret: "expired", ExpiresAt: new(now.Add(-time.Second).UnixMilli())},
+		{Secret: "permanent"},
+	}
+
+	p.RotateSecret(now, time.Hour)
+	assert.Len(t, p.Config.SigningSecret, 32)
+	assert.NotEqual(t, "old", p.Config.SigningSecret)
+	assert.Equal(t, []Secret{
+		{Secret: "old", ExpiresAt: new(now.Add(time.Hour).UnixMilli())},
+		{Secret: "permanent"},
+	}, p.Config.SigningSecrets)
+	assert.Equal(t, []string{p.Config.SigningSecret, "old", "permanent"}, p.Config.ActiveSecrets(now))
+	assert.Equal(t, []string{p.Config.SigningSecret, "permanent"}, p.Config.ActiveSecrets(now.Add(time.Hour)))
+
+	// the config is persisted and loaded
+	config := p.GetConfig()
+	loaded := new(SignaturePlugin)
+	assert.NoError(t, loaded.Init(config))
+	assert.Equal(t, p.Config, loaded.Config)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/webhookx-io/webhookx/pkg/contextx"
 	"github.com/webhookx-io/webhookx/pkg/http/middlewares"
 	"github.com/webhookx-io/webhookx/pkg/http/response"
+	"github.com/webhookx-io/webhookx/pkg/jwk"
 	"github.com/webhookx-io/webhookx/pkg/loglimiter"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/queue"
@@ -131,6 +132,7 @@ func NewGateway(opts Options, services *services.Services) *Gateway {
 	}
 
 	r.Use(middlewares.NewRecovery(customizeErrorResponse).Handle)
+	r.HandleFunc("/.well-known/webhookx/jwks.json", gw.HandleJWKS).Methods(http.MethodGet)
 	r.PathPrefix("/").HandlerFunc(gw.Handle)
 
 	gw.s = &http.Server{
@@ -221,6 +223,13 @@ func (g *Gateway) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleJWKS responds the public keys published by plugins as JWKS (RFC 7517)
+func (g *Gateway) HandleJWKS(w http.ResponseWriter, r *http.Request) {
+	set := jwk.Set{Keys: plugins.LoadIterator().PublicKeys()}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, set)
+}
+
 func (g *Gateway) handleRequest(w http.ResponseWriter, r *http.Request) (*Response, error) {
 	ctx := context.WithoutCancel(r.Context())
 
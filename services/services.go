@@ -3,6 +3,7 @@ package services
 import (
 	"github.com/webhookx-io/webhookx/pkg/metrics"
 	"github.com/webhookx-io/webhookx/pkg/ratelimiter"
+	"github.com/webhookx-io/webhookx/pkg/semaphore"
 	"github.com/webhookx-io/webhookx/services/distributed"
 	"github.com/webhookx-io/webhookx/services/eventbus"
 	"github.com/webhookx-io/webhookx/services/schedule"
@@ -15,5 +16,6 @@ type Services struct {
 	Metrics     *metrics.Metrics
 	Task        *task.TaskService
 	RateLimiter ratelimiter.RateLimiter
+	Semaphore   semaphore.Semaphore
 	Distributed *distributed.Distributed
 }
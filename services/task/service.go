@@ -2,7 +2,6 @@ package task
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/webhookx-io/webhookx/constants"
@@ -44,19 +43,20 @@ func (s *TaskService) ScheduleAttempts(ctx context.Context, attempts []*entities
 			if !notify && attempt.ScheduledAt.Before(now) {
 				notify = true
 			}
-			var eventData json.RawMessage
+			data := &taskqueue.MessageData{
+				EventID:    attempt.EventId,
+				EndpointId: attempt.EndpointId,
+				Attempt:    attempt.AttemptNumber,
+			}
 			if attempt.Event != nil {
-				eventData = attempt.Event.Data
+				data.Event = string(attempt.Event.Data)
+				data.EventType = attempt.Event.EventType
+				data.IngestedAt = attempt.Event.IngestedAt.UnixMilli()
 			}
 			tasks = append(tasks, &taskqueue.TaskMessage{
 				ID:          attempt.ID,
 				ScheduledAt: attempt.ScheduledAt.Time,
-				Data: &taskqueue.MessageData{
-					EventID:    attempt.EventId,
-					EndpointId: attempt.EndpointId,
-					Attempt:    attempt.AttemptNumber,
-					Event:      string(eventData),
-				},
+				Data:        data,
 			})
 			ids = append(ids, attempt.ID)
 		}
@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/admin/api"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("/dead-letters", Ordered, func() {
+
+	var adminClient *resty.Client
+	var app *app.Application
+	var db *db.DB
+	var ws *entities.Workspace
+
+	var endpoints []*entities.Endpoint
+	var deadLetters []*entities.DeadLetter
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, nil)
+		app = utils.Must(helper.Start(nil))
+		ws = utils.Must(db.Workspaces.GetDefault(context.TODO()))
+		adminClient = helper.AdminClient()
+
+		for i := 0; i < 2; i++ {
+			endpoint := factory.EndpointWS(ws.ID)
+			assert.NoError(GinkgoT(), db.Endpoints.Insert(context.TODO(), endpoint))
+			endpoints = append(endpoints, endpoint)
+		}
+
+		for i := 1; i <= 5; i++ {
+			event := factory.EventWS(ws.ID)
+			if i%2 == 0 {
+				event.EventType = "foo.baz"
+			}
+			assert.NoError(GinkgoT(), db.Events.Insert(context.TODO(), event))
+			deadLetter := &entities.DeadLetter{
+				ID:         utils.KSUID(),
+				EventId:    event.ID,
+				EventType:  event.EventType,
+				EndpointId: endpoints[i%2].ID,
+				AttemptId:  utils.KSUID(),
+				Reason:     entities.AttemptExhaustedReasonMaxAttempts,
+				ErrorCode:  new(entities.AttemptErrorCodeTimeout),
+			}
+			deadLetter.WorkspaceId = ws.ID
+			assert.NoError(GinkgoT(), db.DeadLetters.Insert(context.TODO(), deadLetter))
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	Context("GET", func() {
+		It("retrieves dead letters", func() {
+			resp, err := adminClient.R().
+				SetResult(api.Pagination[*entities.DeadLetter]{}).
+				Get("/workspaces/default/dead-letters")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			result := resp.Result().(*api.Pagination[*entities.DeadLetter])
+			assert.EqualValues(GinkgoT(), 5, result.Total)
+			assert.EqualValues(GinkgoT(), 5, len(result.Data))
+		})
+
+		It("query by endpoint_id", func() {
+			resp, err := adminClient.R().
+				SetResult(api.Pagination[*entities.DeadLetter]{}).
+				Get("/workspaces/default/dead-letters?endpoint_id=" + endpoints[0].ID)
+			assert.Nil(GinkgoT(), err)
+			result := resp.Result().(*api.Pagination[*entities.DeadLetter])
+			assert.EqualValues(GinkgoT(), 2, result.Total)
+		})
+
+		It("query by event_type", func() {
+			resp, err := adminClient.R().
+				SetResult(api.Pagination[*entities.DeadLetter]{}).
+				Get("/workspaces/default/dead-letters?event_type=foo.baz")
+			assert.Nil(GinkgoT(), err)
+			result := resp.Result().(*api.Pagination[*entities.DeadLetter])
+			assert.EqualValues(GinkgoT(), 2, result.Total)
+		})
+
+		It("retrieves a dead letter", func() {
+			resp, err := adminClient.R().
+				SetResult(entities.DeadLetter{}).
+				Get("/workspaces/default/dead-letters/" + deadLetters[0].ID)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			result := resp.Result().(*entities.DeadLetter)
+			assert.Equal(GinkgoT(), deadLetters[0].EventId, result.EventId)
+			assert.Equal(GinkgoT(), entities.AttemptExhaustedReasonMaxAttempts, result.Reason)
+			assert.Nil(GinkgoT(), result.ReplayedAt)
+		})
+	})
+
+	Context("POST /replay", func() {
+		It("replays dead letters", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"endpoint_id":    endpoints[1].ID,
+					"batch_size":     1,
+					"batch_interval": 10,
+				}).
+				SetResult(entities.DeadLetterReplay{}).
+				Post("/workspaces/default/dead-letters/replay")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 202, resp.StatusCode())
+			replay := resp.Result().(*entities.DeadLetterReplay)
+			assert.EqualValues(GinkgoT(), 3, replay.Total)
+
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := adminClient.R().
+					SetResult(entities.DeadLetterReplay{}).
+					Get("/workspaces/default/dead-letters/replay/" + replay.ID)
+				if err != nil || resp.StatusCode() != 200 {
+					return false
+				}
+				replay = resp.Result().(*entities.DeadLetterReplay)
+				return replay.Status == entities.DeadLetterReplayStatusCompleted
+			}, time.Second*5, time.Millisecond*100)
+			assert.EqualValues(GinkgoT(), 3, replay.Replayed)
+			assert.EqualValues(GinkgoT(), 0, replay.Failed)
+			assert.NotNil(GinkgoT(), replay.FinishedAt)
+
+			for _, deadLetter := range deadLetters {
+				dl, err := db.DeadLetters.Get(context.TODO(), deadLetter.ID)
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), deadLetter.EndpointId == endpoints[1].ID, dl.ReplayedAt != nil)
+
+				q := dao.AttemptQuery{}
+				q.EventId = &deadLetter.EventId
+				attempts, err := db.Attempts.List(context.TODO(), q.ToQuery())
+				assert.NoError(GinkgoT(), err)
+				if deadLetter.EndpointId == endpoints[1].ID {
+					assert.Equal(GinkgoT(), 1, len(attempts))
+					assert.Equal(GinkgoT(), entities.AttemptTriggerModeManual, attempts[0].TriggerMode)
+				} else {
+					assert.Equal(GinkgoT(), 0, len(attempts))
+				}
+			}
+		})
+
+		It("skips replayed dead letters", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{}).
+				SetResult(entities.DeadLetterReplay{}).
+				Post("/workspaces/default/dead-letters/replay")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 202, resp.StatusCode())
+			replay := resp.Result().(*entities.DeadLetterReplay)
+			assert.EqualValues(GinkgoT(), 2, replay.Total)
+		})
+
+		It("returns HTTP 400 for invalid batch_size", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"batch_size": 0,
+				}).
+				Post("/workspaces/default/dead-letters/replay")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 400, resp.StatusCode())
+			assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"batch_size":"number must be at least 1"}}}`, string(resp.Body()))
+		})
+
+		It("returns HTTP 404 for unknown replay", func() {
+			resp, err := adminClient.R().
+				Get("/workspaces/default/dead-letters/replay/unknown")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 404, resp.StatusCode())
+		})
+	})
+})
@@ -16,6 +16,9 @@ import (
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/test/helper"
 	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/circuitbreaker"
+	"github.com/webhookx-io/webhookx/worker/circuitbreaker/metrics"
+	"github.com/webhookx-io/webhookx/worker/deliverer"
 )
 
 var _ = Describe("/endpoints", Ordered, func() {
@@ -57,6 +60,8 @@ var _ = Describe("/endpoints", Ordered, func() {
 			result := resp.Result().(*entities.Endpoint)
 			assert.NotNil(GinkgoT(), result.ID)
 			assert.Equal(GinkgoT(), true, result.Enabled)
+			assert.Equal(GinkgoT(), entities.EndpointTypeHTTP, result.Type)
+			assert.Nil(GinkgoT(), result.Config)
 
 			assert.Equal(GinkgoT(), "https://example.com", result.Request.URL)
 			assert.Equal(GinkgoT(), "POST", result.Request.Method)
@@ -75,6 +80,179 @@ var _ = Describe("/endpoints", Ordered, func() {
 			assert.Equal(GinkgoT(), e.CreatedAt, e.UpdatedAt)
 		})
 
+		It("creates an endpoint with circuit breaker overrides", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"request": map[string]interface{}{
+						"url": "https://example.com",
+					},
+					"circuit_breaker": map[string]interface{}{
+						"failure_rate_threshold": 50,
+					},
+				}).
+				SetResult(entities.Endpoint{}).
+				Post("/workspaces/default/endpoints")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+			result := resp.Result().(*entities.Endpoint)
+			assert.Equal(GinkgoT(), &entities.CircuitBreaker{
+				Enabled:              true,
+				FailureRateThreshold: new(50),
+			}, result.CircuitBreaker)
+
+			e, err := db.Endpoints.Get(context.TODO(), result.ID)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), result.CircuitBreaker, e.CircuitBreaker)
+		})
+
+		It("creates an endpoint that opts out of circuit breaker", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"request": map[string]interface{}{
+						"url": "https://example.com",
+					},
+					"circuit_breaker": map[string]interface{}{
+						"enabled": false,
+					},
+				}).
+				SetResult(entities.Endpoint{}).
+				Post("/workspaces/default/endpoints")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+			result := resp.Result().(*entities.Endpoint)
+			assert.Equal(GinkgoT(), &entities.CircuitBreaker{Enabled: false}, result.CircuitBreaker)
+		})
+
+		It("creates a kafka endpoint", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"type": "kafka",
+					"request": map[string]interface{}{
+						"url": "kafka://localhost:9092",
+					},
+					"config": map[string]interface{}{
+						"kafka": map[string]interface{}{
+							"topic": "events",
+						},
+					},
+				}).
+				SetResult(entities.Endpoint{}).
+				Post("/workspaces/default/endpoints")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+			result := resp.Result().(*entities.Endpoint)
+			assert.Equal(GinkgoT(), deliverer.EndpointTypeKafka, result.Type)
+			assert.Equal(GinkgoT(), "events", result.Config["kafka"]["topic"])
+
+			e, err := db.Endpoints.Get(context.TODO(), result.ID)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), result.Config, e.Config)
+		})
+
+		It("creates an endpoint with capture", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"request": map[string]interface{}{
+						"url": "https://example.com",
+					},
+					"capture": map[string]interface{}{
+						"mode":           "headers_only",
+						"redact_headers": []string{"Authorization"},
+					},
+				}).
+				SetResult(entities.Endpoint{}).
+				Post("/workspaces/default/endpoints")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+			result := resp.Result().(*entities.Endpoint)
+			assert.Equal(GinkgoT(), &entities.Capture{
+				Mode:          entities.CaptureModeHeadersOnly,
+				RedactHeaders: []string{"Authorization"},
+			}, result.Capture)
+
+			e, err := db.Endpoints.Get(context.TODO(), result.ID)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), result.Capture, e.Capture)
+		})
+
+		It("creates an endpoint with compression", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"request": map[string]interface{}{
+						"url":         "https://example.com",
+						"compression": map[string]interface{}{},
+					},
+				}).
+				SetResult(entities.Endpoint{}).
+				Post("/workspaces/default/endpoints")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+			result := resp.Result().(*entities.Endpoint)
+			assert.Equal(GinkgoT(), &entities.Compression{
+				Algorithm: "gzip",
+				MinSize:   1024,
+				Stage:     entities.CompressionStageAfterPlugins,
+			}, result.Request.Compression)
+		})
+
+		It("creates an endpoint with exponential retry strategy", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"request": map[string]interface{}{
+						"url": "https://example.com",
+					},
+					"retry": map[string]interface{}{
+						"strategy": "exponential",
+						"config": map[string]interface{}{
+							"initial_delay": 5,
+							"multiplier":    1.5,
+							"max_delay":     600,
+							"max_attempts":  8,
+							"jitter":        "full",
+						},
+					},
+				}).
+				SetResult(entities.Endpoint{}).
+				Post("/workspaces/default/endpoints")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+			result := resp.Result().(*entities.Endpoint)
+			assert.Equal(GinkgoT(), entities.RetryStrategyExponential, result.Retry.Strategy)
+			assert.Nil(GinkgoT(), result.Retry.Config.Attempts)
+			assert.EqualValues(GinkgoT(), 5, result.Retry.Config.InitialDelay)
+			assert.EqualValues(GinkgoT(), 1.5, result.Retry.Config.Multiplier)
+			assert.EqualValues(GinkgoT(), 600, result.Retry.Config.MaxDelay)
+			assert.EqualValues(GinkgoT(), 8, result.Retry.Config.MaxAttempts)
+			assert.Equal(GinkgoT(), entities.RetryJitterFull, result.Retry.Config.Jitter)
+		})
+
+		It("creates an endpoint with empty fixed retry config", func() {
+			resp, err := adminClient.R().
+				SetBody(map[string]interface{}{
+					"request": map[string]interface{}{
+						"url": "https://example.com",
+					},
+					"retry": map[string]interface{}{
+						"strategy": "fixed",
+						"config":   map[string]interface{}{},
+					},
+				}).
+				SetResult(entities.Endpoint{}).
+				Post("/workspaces/default/endpoints")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+			result := resp.Result().(*entities.Endpoint)
+			assert.Equal(GinkgoT(), entities.RetryStrategyFixed, result.Retry.Strategy)
+			assert.Equal(GinkgoT(), []int64{0, 60, 3600}, result.Retry.Config.Attempts)
+		})
+
 		Context("errors", func() {
 			It("returns HTTP 400 for invalid json", func() {
 				resp, err := adminClient.R().
@@ -170,6 +348,206 @@ var _ = Describe("/endpoints", Ordered, func() {
 				assert.JSONEq(GinkgoT(), expected, string(resp.Body()))
 			})
 
+			It("returns HTTP 400 for invalid retry config", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+						},
+						"retry": map[string]interface{}{
+							"strategy": "exponential",
+							"config": map[string]interface{}{
+								"initial_delay": 60,
+								"max_delay":     10,
+							},
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"retry":{"config":{"max_delay":"value must be >= initial_delay"}}}}}`, string(resp.Body()))
+
+				resp, err = adminClient.R().
+					SetBody(map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+						},
+						"retry": map[string]interface{}{
+							"policy": map[string]interface{}{
+								"status_codes": []string{"429", "504-500"},
+							},
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"retry":{"policy":{"status_codes":[null,"invalid status code range: 504-500"]}}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for tls client_cert without client_key", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+							"tls": map[string]interface{}{
+								"client_cert": "{secret://vault/webhookx/mtls.cert}",
+							},
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"request":{"tls":{"client_key":"required with client_cert"}}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for tls secret reference without secret manager", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+							"tls": map[string]interface{}{
+								"ca_cert": "{secret://vault/webhookx/mtls.ca}",
+							},
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"request":{"tls":{"ca_cert":"secret reference is not supported as secret manager is not enabled"}}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for batch delivery mode with ordering", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+						},
+						"delivery": map[string]interface{}{
+							"mode": "batch",
+						},
+						"ordering": map[string]interface{}{},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"delivery":{"mode":"batch mode cannot be used with ordering"}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for kafka endpoint without kafka config", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"type": "kafka",
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"config":{"kafka":"required field missing"},"request":{"url":"scheme must be one of [kafka] for kafka endpoint"}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for compression of non-http endpoint", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"type": "nats",
+						"request": map[string]interface{}{
+							"url":         "nats://localhost:4222",
+							"compression": map[string]interface{}{},
+						},
+						"config": map[string]interface{}{
+							"nats": map[string]interface{}{
+								"subject": "events",
+							},
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"request":{"compression":"compression is only supported by http endpoints"}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for unknown endpoint type", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"type": "unknown",
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"type":"unknown endpoint type 'unknown'"}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for grpc endpoint with invalid method", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"type": "grpc",
+						"request": map[string]interface{}{
+							"url": "grpc://localhost:50051",
+						},
+						"config": map[string]interface{}{
+							"grpc": map[string]interface{}{
+								"method": "Check",
+							},
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"config":{"grpc":{"method":"method must be in the form of package.Service/Method"}}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for grpc endpoint with invalid descriptor set", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"type": "grpc",
+						"request": map[string]interface{}{
+							"url": "grpc://localhost:50051",
+						},
+						"config": map[string]interface{}{
+							"grpc": map[string]interface{}{
+								"method":         "grpc.health.v1.Health/Check",
+								"descriptor_set": "",
+							},
+						},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"config":{"grpc":{"descriptor_set":"service grpc.health.v1.Health not found"}}}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for invalid event patterns", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+						},
+						"events": []string{"order.*", "order.**.created", "order*", "order..*"},
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"events":[null,"'**' must be the last segment: order.**.created","wildcard must be a whole segment: order*","invalid event pattern: order..*"]}}}`, string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for invalid filter", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "https://example.com",
+						},
+						"filter": "data.amount > 100; data.currency === 'USD'",
+					}).
+					Post("/workspaces/default/endpoints")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"Request Validation","error":{"message":"request validation","fields":{"filter":"filter must be a single expression"}}}`, string(resp.Body()))
+			})
+
 			It("return HTTP 400 for invalid rate_limit: missing required properties", func() {
 				resp, err := adminClient.R().
 					SetBody(map[string]interface{}{
@@ -655,6 +1033,80 @@ var _ = Describe("/endpoints", Ordered, func() {
 				})
 			})
 		})
+
+		Context("health", func() {
+			var entity *entities.Endpoint
+			BeforeAll(func() {
+				entity = &entities.Endpoint{
+					ID:      utils.KSUID(),
+					Enabled: true,
+					Request: entities.RequestConfig{
+						URL:    "https://example.com",
+						Method: "POST",
+					},
+				}
+				entity.WorkspaceId = ws.ID
+				assert.Nil(GinkgoT(), db.Endpoints.Insert(context.TODO(), entity))
+
+				manager := circuitbreaker.NewManager(
+					circuitbreaker.WithRedisClient(app.Config().Redis.GetClient()),
+					circuitbreaker.WithMinimumRequestThreshold(5),
+				)
+				now := time.Now().Add(-time.Second * 2)
+				manager.Record(now, entity.ID, metrics.Success)
+				for i := 0; i < 9; i++ {
+					manager.Record(now, entity.ID, metrics.Error)
+				}
+				assert.Nil(GinkgoT(), manager.Flush(context.TODO()))
+				_, opened, err := manager.Evaluate(context.TODO(), entity.ID, nil)
+				assert.Nil(GinkgoT(), err)
+				assert.True(GinkgoT(), opened)
+			})
+
+			It("retrieves the health", func() {
+				resp, err := adminClient.R().
+					SetResult(api.EndpointHealth{}).
+					Get("/workspaces/default/endpoints/" + entity.ID + "/health")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+				result := resp.Result().(*api.EndpointHealth)
+				assert.True(GinkgoT(), result.Enabled)
+				assert.Equal(GinkgoT(), circuitbreaker.StateOpen, result.State)
+				assert.EqualValues(GinkgoT(), 1, result.Metric.Success)
+				assert.EqualValues(GinkgoT(), 9, result.Metric.Error)
+				assert.InDelta(GinkgoT(), 0.9, result.FailureRate, 0.001)
+				assert.NotEmpty(GinkgoT(), result.Buckets)
+				assert.NotNil(GinkgoT(), result.OpenedAt)
+				assert.Equal(GinkgoT(), "failure rate 90.00% exceeded the threshold 80% (10 requests)", *result.Reason)
+				assert.Nil(GinkgoT(), result.ResetAt)
+			})
+
+			It("resets the health", func() {
+				resp, err := adminClient.R().
+					SetResult(api.EndpointHealth{}).
+					Post("/workspaces/default/endpoints/" + entity.ID + "/health/reset")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+				result := resp.Result().(*api.EndpointHealth)
+				assert.Equal(GinkgoT(), circuitbreaker.StateClosed, result.State)
+				assert.EqualValues(GinkgoT(), 0, result.Metric.TotalRequest())
+				assert.Nil(GinkgoT(), result.OpenedAt)
+				assert.Nil(GinkgoT(), result.Reason)
+				assert.NotNil(GinkgoT(), result.ResetAt)
+			})
+
+			Context("errors", func() {
+				It("return HTTP 404", func() {
+					resp, err := adminClient.R().Get("/workspaces/default/endpoints/notfound/health")
+					assert.Nil(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 404, resp.StatusCode())
+
+					resp, err = adminClient.R().Post("/workspaces/default/endpoints/notfound/health/reset")
+					assert.Nil(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 404, resp.StatusCode())
+				})
+			})
+		})
 	})
 
 })
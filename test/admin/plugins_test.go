@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	. "github.com/onsi/ginkgo/v2"
@@ -213,6 +214,27 @@ var _ = Describe("/plugins", Ordered, func() {
 					`{"message":"Request Validation","error":{"message":"request validation","fields":{"config":{"function":"maximum string length is 1048576"}}}}`,
 					string(resp.Body()))
 			})
+
+			It("return 201 when applied to endpoint", func() {
+				endpoint := factory.Endpoint()
+				assert.Nil(GinkgoT(), db.Endpoints.Insert(context.TODO(), endpoint))
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":        "function",
+						"endpoint_id": endpoint.ID,
+						"config": map[string]string{
+							"function": "function handle() { webhookx.delivery.skip() }",
+						},
+					}).
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins")
+
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 201, resp.StatusCode())
+				result := resp.Result().(*entities.Plugin)
+				assert.Equal(GinkgoT(), endpoint.ID, *result.EndpointId)
+				assert.Nil(GinkgoT(), result.SourceId)
+			})
 		})
 
 		Context("basic-auth plugin", func() {
@@ -472,6 +494,18 @@ var _ = Describe("/plugins", Ordered, func() {
 					string(resp.Body()))
 			})
 
+			It("returns HTTP 400 when missing both source_id and endpoint_id for plugin of both types", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{"name": "function", "config": map[string]interface{}{"function": "function handle() {}"}}).
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"endpoint_id":"source_id or endpoint_id is required for plugin 'function'","source_id":"source_id or endpoint_id is required for plugin 'function'"}}}`,
+					string(resp.Body()))
+			})
+
 			It("returns HTTP 400 when missing required config fields", func() {
 				cancel := helper.ReplaceLicenser(nil)
 				defer cancel()
@@ -714,6 +748,74 @@ var _ = Describe("/plugins", Ordered, func() {
 				})
 			})
 		})
+
+		Context("POST /rotate", func() {
+			var endpoint *entities.Endpoint
+			var entity *entities.Plugin
+
+			BeforeAll(func() {
+				endpoint = factory.EndpointWS(ws.ID)
+				assert.Nil(GinkgoT(), db.Endpoints.Insert(context.TODO(), endpoint))
+
+				entity = factory.PluginWS("webhookx-signature", ws.ID, func(o *entities.Plugin) {
+					o.EndpointId = new(endpoint.ID)
+					o.Config = map[string]interface{}{"signing_secret": "old"}
+				})
+				assert.Nil(GinkgoT(), db.Plugins.Insert(context.TODO(), entity))
+			})
+
+			It("rotates the signing secret", func() {
+				resp, err := adminClient.R().
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins/" + entity.ID + "/rotate?overlap=3600")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+				result := resp.Result().(*entities.Plugin)
+				secret := result.Config["signing_secret"].(string)
+				assert.Equal(GinkgoT(), 32, len(secret))
+				secrets := result.Config["signing_secrets"].([]interface{})
+				assert.Len(GinkgoT(), secrets, 1)
+				assert.Equal(GinkgoT(), "old", secrets[0].(map[string]interface{})["secret"])
+				expiresAt := int64(secrets[0].(map[string]interface{})["expires_at"].(float64))
+				assert.InDelta(GinkgoT(), time.Now().Add(time.Hour).UnixMilli(), expiresAt, 5000)
+
+				e, err := db.Plugins.Get(context.TODO(), entity.ID)
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), secret, e.Config["signing_secret"])
+			})
+
+			Context("errors", func() {
+				It("return HTTP 400 when plugin does not support rotation", func() {
+					p := factory.PluginWS("outbound", ws.ID, func(o *entities.Plugin) {
+						o.EndpointId = new(endpoint.ID)
+					})
+					assert.Nil(GinkgoT(), db.Plugins.Insert(context.TODO(), p))
+
+					resp, err := adminClient.R().Post("/workspaces/default/plugins/" + p.ID + "/rotate")
+					assert.Nil(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 400, resp.StatusCode())
+					assert.Equal(GinkgoT(), `{"message":"plugin 'outbound' does not support secret rotation"}`, string(resp.Body()))
+				})
+
+				It("return HTTP 400 when overlap is invalid", func() {
+					resp, err := adminClient.R().Post("/workspaces/default/plugins/" + entity.ID + "/rotate?overlap=-1")
+					assert.Nil(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 400, resp.StatusCode())
+
+					resp, err = adminClient.R().Post("/workspaces/default/plugins/" + entity.ID + "/rotate?overlap=99999999999999999999")
+					assert.Nil(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				})
+
+				It("return HTTP 404", func() {
+					resp, err := adminClient.R().Post("/workspaces/default/plugins/notfound/rotate")
+					assert.Nil(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 404, resp.StatusCode())
+					assert.Equal(GinkgoT(), `{"message":"Not found"}`, string(resp.Body()))
+				})
+			})
+		})
 	})
 
 })
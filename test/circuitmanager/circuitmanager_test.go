@@ -46,8 +46,9 @@ var _ = Describe("CircuitBreaker Manager", Ordered, func() {
 			err := manager.Flush(context.TODO())
 			assert.NoError(GinkgoT(), err)
 
-			cb, err := manager.GetCircuitBreaker(context.TODO(), "test")
+			cb, opened, err := manager.Evaluate(context.TODO(), "test", nil)
 			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), opened)
 			assert.Equal(GinkgoT(), "test", cb.Name())
 			assert.EqualValues(GinkgoT(), 1, cb.Metric().Success)
 			assert.EqualValues(GinkgoT(), 4, cb.Metric().Error)
@@ -57,6 +58,47 @@ var _ = Describe("CircuitBreaker Manager", Ordered, func() {
 		})
 	})
 
+	Context("thresholds", func() {
+		manager := circuitbreaker.NewManager(
+			circuitbreaker.WithRedisClient(redisClient()),
+			circuitbreaker.WithTimeWindowSize(60),
+			circuitbreaker.WithFailureRateThreshold(80),
+			circuitbreaker.WithMinimumRequestThreshold(5),
+		)
+
+		It("evaluates with overridden thresholds", func() {
+			redisClient().FlushDB(context.TODO())
+
+			manager.Record(time.Now().Add(-time.Second), "test", metrics.Success)
+			manager.Record(time.Now().Add(-time.Second), "test", metrics.Success)
+			manager.Record(time.Now().Add(-time.Second), "test", metrics.Error)
+			manager.Record(time.Now().Add(-time.Second), "test", metrics.Error)
+			manager.Record(time.Now().Add(-time.Second), "test", metrics.Error)
+			assert.NoError(GinkgoT(), manager.Flush(context.TODO()))
+
+			cb, opened, err := manager.Evaluate(context.TODO(), "test", nil)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), opened)
+			assert.Equal(GinkgoT(), circuitbreaker.StateClosed, cb.State())
+
+			cb, opened, err = manager.Evaluate(context.TODO(), "test", &circuitbreaker.Thresholds{
+				FailureRateThreshold:    50,
+				MinimumRequestThreshold: 10,
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), opened)
+			assert.Equal(GinkgoT(), circuitbreaker.StateClosed, cb.State())
+
+			cb, opened, err = manager.Evaluate(context.TODO(), "test", &circuitbreaker.Thresholds{
+				FailureRateThreshold: 50,
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), opened)
+			assert.Equal(GinkgoT(), circuitbreaker.StateOpen, cb.State())
+			assert.Equal(GinkgoT(), "failure rate 60.00% exceeded the threshold 50% (5 requests)", cb.Reason())
+		})
+	})
+
 	Context("windowsize >= 3600", func() {
 		t := time.Now()
 		BeforeAll(func() {
@@ -88,7 +130,7 @@ var _ = Describe("CircuitBreaker Manager", Ordered, func() {
 				circuitbreaker.WithNowFunc(func() time.Time { return t.Truncate(time.Hour).Add(time.Minute * 30) }),
 			)
 
-			cb, err := manager.GetCircuitBreaker(context.TODO(), "test")
+			cb, err := manager.GetCircuitBreaker(context.TODO(), "test", nil)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), "test", cb.Name())
 			assert.EqualValues(GinkgoT(), 51, cb.Metric().Success)
@@ -101,6 +143,102 @@ var _ = Describe("CircuitBreaker Manager", Ordered, func() {
 	})
 })
 
+var _ = Describe("CircuitBreaker state", Ordered, func() {
+	now := time.Now()
+	manager := circuitbreaker.NewManager(
+		circuitbreaker.WithRedisClient(redisClient()),
+		circuitbreaker.WithTimeWindowSize(60),
+		circuitbreaker.WithFailureRateThreshold(80),
+		circuitbreaker.WithMinimumRequestThreshold(5),
+		circuitbreaker.WithCooldown(10),
+		circuitbreaker.WithHalfOpenProbes(2),
+		circuitbreaker.WithHalfOpenWait(time.Second),
+		circuitbreaker.WithNowFunc(func() time.Time { return now }),
+	)
+
+	BeforeAll(func() {
+		redisClient().FlushDB(context.TODO())
+		for i := 0; i < 5; i++ {
+			manager.Record(now.Add(-time.Second), "test", metrics.Error)
+		}
+		assert.NoError(GinkgoT(), manager.Flush(context.TODO()))
+	})
+
+	It("CLOSED allows requests", func() {
+		permit, err := manager.Acquire(context.TODO(), "test")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), circuitbreaker.StateClosed, permit.State)
+		assert.True(GinkgoT(), permit.Allowed)
+		assert.False(GinkgoT(), permit.IsProbe())
+	})
+
+	It("OPEN rejects requests until cool-down elapsed", func() {
+		_, opened, err := manager.Evaluate(context.TODO(), "test", nil)
+		assert.NoError(GinkgoT(), err)
+		assert.True(GinkgoT(), opened)
+
+		// evaluates again
+		cb, opened, err := manager.Evaluate(context.TODO(), "test", nil)
+		assert.NoError(GinkgoT(), err)
+		assert.False(GinkgoT(), opened)
+		assert.Equal(GinkgoT(), circuitbreaker.StateOpen, cb.State())
+
+		permit, err := manager.Acquire(context.TODO(), "test")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), circuitbreaker.StateOpen, permit.State)
+		assert.False(GinkgoT(), permit.Allowed)
+		assert.Equal(GinkgoT(), now.Add(time.Second*10).UnixMilli(), permit.RetryAt.UnixMilli())
+	})
+
+	It("HALF_OPEN allows limited probes", func() {
+		now = now.Add(time.Second * 10)
+		for i := 0; i < 2; i++ {
+			permit, err := manager.Acquire(context.TODO(), "test")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), circuitbreaker.StateHalfOpen, permit.State)
+			assert.True(GinkgoT(), permit.IsProbe())
+		}
+		permit, err := manager.Acquire(context.TODO(), "test")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), circuitbreaker.StateHalfOpen, permit.State)
+		assert.False(GinkgoT(), permit.Allowed)
+		assert.Equal(GinkgoT(), now.Add(time.Second), permit.RetryAt)
+	})
+
+	It("failed probe re-opens", func() {
+		state, err := manager.ReportProbe(context.TODO(), "test", false)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), circuitbreaker.StateOpen, state)
+
+		permit, err := manager.Acquire(context.TODO(), "test")
+		assert.NoError(GinkgoT(), err)
+		assert.False(GinkgoT(), permit.Allowed)
+	})
+
+	It("succeeded probes close", func() {
+		now = now.Add(time.Second * 10)
+		for i := 0; i < 2; i++ {
+			permit, err := manager.Acquire(context.TODO(), "test")
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), permit.IsProbe())
+		}
+
+		state, err := manager.ReportProbe(context.TODO(), "test", true)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), circuitbreaker.StateHalfOpen, state)
+		state, err = manager.ReportProbe(context.TODO(), "test", true)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), circuitbreaker.StateClosed, state)
+
+		// the failures before closed are excluded
+		cb, opened, err := manager.Evaluate(context.TODO(), "test", nil)
+		assert.NoError(GinkgoT(), err)
+		assert.False(GinkgoT(), opened)
+		assert.Equal(GinkgoT(), circuitbreaker.StateClosed, cb.State())
+		assert.EqualValues(GinkgoT(), 0, cb.Metric().TotalRequest())
+	})
+})
+
 func Test(t *testing.T) {
 	gomega.RegisterFailHandler(Fail)
 	RunSpecs(t, "CircuitBreaker Manager Suite")
@@ -23,11 +23,22 @@ var statusOutputInit = `1 init (⏳ pending)
 1762423418 source_config (⏳ pending)
 1786435500 drop_source_unique_name_constraint (⏳ pending)
 1786614568 retention (⏳ pending)
+1792227600 retry_after (⏳ pending)
+1792231200 retry_policy (⏳ pending)
+1792234800 dead_letters (⏳ pending)
+1792238400 circuit_breaker (⏳ pending)
+1792242000 ordering (⏳ pending)
+1792245600 max_concurrency (⏳ pending)
+1792249200 delivery (⏳ pending)
+1792252800 filter (⏳ pending)
+1792256400 endpoint_type (⏳ pending)
+1792260000 capture (⏳ pending)
+1792263600 dead_letter_replays (⏳ pending)
 Summary:
   Current version: 0
   Dirty: false
   Executed: 0
-  Pending: 14
+  Pending: 25
 `
 
 var statusOutputDone = `1 init (✅ executed)
@@ -44,10 +55,21 @@ var statusOutputDone = `1 init (✅ executed)
 1762423418 source_config (✅ executed)
 1786435500 drop_source_unique_name_constraint (✅ executed)
 1786614568 retention (✅ executed)
+1792227600 retry_after (✅ executed)
+1792231200 retry_policy (✅ executed)
+1792234800 dead_letters (✅ executed)
+1792238400 circuit_breaker (✅ executed)
+1792242000 ordering (✅ executed)
+1792245600 max_concurrency (✅ executed)
+1792249200 delivery (✅ executed)
+1792252800 filter (✅ executed)
+1792256400 endpoint_type (✅ executed)
+1792260000 capture (✅ executed)
+1792263600 dead_letter_replays (✅ executed)
 Summary:
-  Current version: 1786614568
+  Current version: 1792263600
   Dirty: false
-  Executed: 14
+  Executed: 25
   Pending: 0
 `
 
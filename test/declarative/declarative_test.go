@@ -12,6 +12,7 @@ import (
 	"github.com/webhookx-io/webhookx/app"
 	"github.com/webhookx-io/webhookx/test/helper"
 	"github.com/webhookx-io/webhookx/utils"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -26,6 +27,24 @@ sources:
       enabled: ok
 `
 
+	eventPatternsYAML = `
+endpoints:
+  - name: pattern-endpoint
+    request:
+      url: https://httpbin.org/anything
+    events: [ "order.*", "*.created", "order.**", "*" ]
+`
+
+	emptyFixedRetryYAML = `
+endpoints:
+  - name: retry-endpoint
+    request:
+      url: https://httpbin.org/anything
+    retry:
+      strategy: fixed
+      config: {}
+`
+
 	invalidEndpointYAML = `
 endpoints:
   - name: default-endpoint
@@ -86,6 +105,50 @@ var _ = Describe("Declarative", Ordered, func() {
 			assert.Equal(GinkgoT(), 200, resp.StatusCode())
 		})
 
+		It("should keep event patterns verbatim", func() {
+			resp, err := adminClient.R().
+				SetBody(eventPatternsYAML).
+				Post("/workspaces/default/config/sync")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			resp, err = adminClient.R().Post("/workspaces/default/config/dump")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			var cfg struct {
+				Endpoints []struct {
+					Events []string `yaml:"events"`
+				} `yaml:"endpoints"`
+			}
+			assert.NoError(GinkgoT(), yaml.Unmarshal(resp.Body(), &cfg))
+			assert.Len(GinkgoT(), cfg.Endpoints, 1)
+			assert.Equal(GinkgoT(), []string{"order.*", "*.created", "order.**", "*"}, cfg.Endpoints[0].Events)
+		})
+
+		It("should fill the default attempts of empty fixed retry config", func() {
+			resp, err := adminClient.R().
+				SetBody(emptyFixedRetryYAML).
+				Post("/workspaces/default/config/sync")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			resp, err = adminClient.R().Post("/workspaces/default/config/dump")
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			var cfg struct {
+				Endpoints []struct {
+					Retry struct {
+						Config struct {
+							Attempts []int64 `yaml:"attempts"`
+						} `yaml:"config"`
+					} `yaml:"retry"`
+				} `yaml:"endpoints"`
+			}
+			assert.NoError(GinkgoT(), yaml.Unmarshal(resp.Body(), &cfg))
+			assert.Len(GinkgoT(), cfg.Endpoints, 1)
+			assert.Equal(GinkgoT(), []int64{0, 60, 3600}, cfg.Endpoints[0].Retry.Config.Attempts)
+		})
+
 		Context("errors", func() {
 			It("should return 400 for malformed yaml", func() {
 				resp, err := adminClient.R().
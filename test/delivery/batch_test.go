@@ -0,0 +1,183 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/worker"
+)
+
+var _ = Describe("batch", Ordered, func() {
+	Context("endpoint with batch delivery mode", func() {
+		var proxyClient *resty.Client
+		var app *app.Application
+		var db *db.DB
+		var server *http.Server
+
+		var mux sync.Mutex
+		var batches [][]worker.BatchItem
+		var batchIds []string
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+					o.Request.URL = "http://localhost:9996"
+					o.Retry.Config.Attempts = []int64{0, 1}
+					o.Delivery = &entities.Delivery{
+						Mode:      entities.DeliveryModeBatch,
+						MaxEvents: 5,
+						MaxBytes:  1048576,
+						MaxWait:   3000,
+					}
+				})},
+				Sources: []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				var items []worker.BatchItem
+				_ = json.Unmarshal(b, &items)
+
+				mux.Lock()
+				defer mux.Unlock()
+				batches = append(batches, items)
+				batchIds = append(batchIds, r.Header.Get("Webhookx-Batch-Id"))
+				// the first batch fails
+				if len(batches) == 1 {
+					w.WriteHeader(500)
+					return
+				}
+				w.WriteHeader(200)
+			}, ":9996")
+
+			app = helper.MustStart(map[string]string{})
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("events should be delivered and retried in one batch", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			for i := 1; i <= 5; i++ {
+				resp, err := proxyClient.R().
+					SetBody(fmt.Sprintf(`{"event_type": "foo.bar","data": {"n": %d}}`, i)).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				q := dao.AttemptQuery{Status: new(entities.AttemptStatusSuccess)}
+				n, err := db.Attempts.Count(context.TODO(), q.ToQuery())
+				assert.NoError(GinkgoT(), err)
+				return n == 5
+			}, time.Second*15, time.Millisecond*100)
+
+			q := dao.AttemptQuery{Status: new(entities.AttemptStatusFailure)}
+			n, err := db.Attempts.Count(context.TODO(), q.ToQuery())
+			assert.NoError(GinkgoT(), err)
+			assert.EqualValues(GinkgoT(), 5, n)
+
+			mux.Lock()
+			defer mux.Unlock()
+			assert.Len(GinkgoT(), batches, 2)
+			for _, items := range batches {
+				assert.Len(GinkgoT(), items, 5)
+			}
+			assert.NotEmpty(GinkgoT(), batchIds[0])
+			assert.NotEqual(GinkgoT(), batchIds[0], batchIds[1])
+		})
+	})
+
+	Context("endpoint with batch delivery mode and rate limit", func() {
+		var proxyClient *resty.Client
+		var app *app.Application
+		var db *db.DB
+		var server *http.Server
+
+		var mux sync.Mutex
+		var batches [][]worker.BatchItem
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+					o.Request.URL = "http://localhost:9996"
+					o.Delivery = &entities.Delivery{
+						Mode:      entities.DeliveryModeBatch,
+						MaxEvents: 5,
+						MaxBytes:  1048576,
+						MaxWait:   3000,
+					}
+					o.RateLimit = &entities.RateLimit{
+						Quota:  1,
+						Period: 60,
+					}
+				})},
+				Sources: []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				var items []worker.BatchItem
+				_ = json.Unmarshal(b, &items)
+
+				mux.Lock()
+				defer mux.Unlock()
+				batches = append(batches, items)
+				w.WriteHeader(200)
+			}, ":9996")
+
+			app = helper.MustStart(map[string]string{})
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("a batch is charged once against the rate limit", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			for i := 1; i <= 5; i++ {
+				resp, err := proxyClient.R().
+					SetBody(fmt.Sprintf(`{"event_type": "foo.bar","data": {"n": %d}}`, i)).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				q := dao.AttemptQuery{Status: new(entities.AttemptStatusSuccess)}
+				n, err := db.Attempts.Count(context.TODO(), q.ToQuery())
+				assert.NoError(GinkgoT(), err)
+				return n == 5
+			}, time.Second*15, time.Millisecond*100)
+
+			mux.Lock()
+			defer mux.Unlock()
+			assert.Len(GinkgoT(), batches, 1)
+			assert.Len(GinkgoT(), batches[0], 5)
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/constants"
 	"github.com/webhookx-io/webhookx/db"
 	"github.com/webhookx-io/webhookx/db/dao"
 	"github.com/webhookx-io/webhookx/db/entities"
@@ -45,6 +46,8 @@ var _ = Describe("CircuitBreaker", Ordered, func() {
 				"WEBHOOKX_WORKER_CIRCUITBREAKER_WINDOW_SIZE":               "3600",
 				"WEBHOOKX_WORKER_CIRCUITBREAKER_FAILURE_RATE_THRESHOLD":    "90",
 				"WEBHOOKX_WORKER_CIRCUITBREAKER_MINIMUM_REQUEST_THRESHOLD": "10",
+				"WEBHOOKX_WORKER_CIRCUITBREAKER_COOLDOWN":                  "2",
+				"WEBHOOKX_WORKER_CIRCUITBREAKER_HALF_OPEN_PROBES":          "1",
 			})
 
 			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
@@ -56,7 +59,7 @@ var _ = Describe("CircuitBreaker", Ordered, func() {
 			circuitbreaker.DefaultFlushInterval = flushInterval
 		})
 
-		It("endpoint should be disabled", func() {
+		It("delivery should be paused and recovered", func() {
 			for i := 0; i < 10; i++ {
 				resp, err := proxyClient.R().
 					SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
@@ -76,9 +79,48 @@ var _ = Describe("CircuitBreaker", Ordered, func() {
 			time.Sleep(time.Second * 2)
 			app.Scheduler().RunNow("worker.detectEndpointHealthy")
 
+			// endpoint should not be disabled
 			endpoint, err := db.Endpoints.Get(context.TODO(), endpoint.ID)
 			assert.NoError(GinkgoT(), err)
-			assert.Equal(GinkgoT(), false, endpoint.Enabled)
+			assert.Equal(GinkgoT(), true, endpoint.Enabled)
+
+			// fix the endpoint
+			resp, err := helper.AdminClient().R().
+				SetBody(map[string]interface{}{
+					"request": map[string]interface{}{
+						"url": "http://localhost:9999/anything",
+					},
+				}).
+				Put("/workspaces/default/endpoints/" + endpoint.ID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			resp, err = proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			eventId := resp.Header().Get(constants.HeaderEventId)
+
+			// the attempt is held back while circuit breaker is open
+			time.Sleep(time.Second)
+			q := dao.AttemptQuery{EventId: &eventId}
+			attempts, err := db.Attempts.List(context.TODO(), q.ToQuery())
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 1, len(attempts))
+			assert.Equal(GinkgoT(), entities.AttemptStatusQueued, attempts[0].Status)
+
+			// the attempt is delivered as a probe after cool-down elapsed
+			assert.Eventually(GinkgoT(), func() bool {
+				attempt, err := db.Attempts.Get(context.TODO(), attempts[0].ID)
+				assert.NoError(GinkgoT(), err)
+				return attempt.Status == entities.AttemptStatusSuccess
+			}, time.Second*5, time.Millisecond*100)
+
+			manager := circuitbreaker.NewManager(circuitbreaker.WithRedisClient(app.Config().Redis.GetClient()))
+			cb, err := manager.GetCircuitBreaker(context.TODO(), endpoint.ID, nil)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), circuitbreaker.StateClosed, cb.State())
 		})
 
 	})
@@ -0,0 +1,147 @@
+package delivery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/compression"
+	"github.com/webhookx-io/webhookx/plugins/webhookx_signature"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+)
+
+var _ = Describe("compression", Ordered, func() {
+	var app *app.Application
+	var db *db.DB
+	var server *http.Server
+	received := make(chan string, 1)
+	data := `{"message": "` + strings.Repeat("a", 2048) + `"}`
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, &helper.TestEntities{
+			Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+				o.Request.URL = "http://localhost:9991"
+				o.Request.Compression = &entities.Compression{
+					Algorithm: compression.Zstd,
+					MinSize:   1024,
+					Stage:     entities.CompressionStageAfterPlugins,
+				}
+			})},
+			Sources: []*entities.Source{factory.Source()},
+		})
+
+		server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			body, err := compression.Decompress(r.Header.Get("Content-Encoding"), b)
+			if err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			received <- string(body)
+			w.WriteHeader(200)
+		}, ":9991")
+
+		app = helper.MustStart(map[string]string{})
+		err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+		assert.NoError(GinkgoT(), err)
+	})
+
+	AfterAll(func() {
+		app.Stop()
+		_ = server.Shutdown(context.TODO())
+	})
+
+	It("delivers the compressed body and stores the uncompressed body", func() {
+		resp, err := helper.ProxyClient().R().
+			SetBody(`{"event_type": "foo.bar","data": ` + data + `}`).
+			Post("/")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+		select {
+		case body := <-received:
+			assert.Equal(GinkgoT(), data, body)
+		case <-time.After(time.Second * 10):
+			Fail("no request received")
+		}
+
+		attempt := waitForAttempt(db, entities.AttemptStatusSuccess)
+		var detail *entities.AttemptDetail
+		assert.Eventually(GinkgoT(), func() bool {
+			detail, err = db.AttemptDetails.Get(context.TODO(), attempt.ID)
+			return err == nil && detail != nil
+		}, time.Second*5, time.Millisecond*100)
+		assert.Equal(GinkgoT(), data, *detail.RequestBody)
+		assert.Equal(GinkgoT(), "zstd", detail.RequestHeaders["Content-Encoding"])
+	})
+})
+
+var _ = Describe("compression before signing with plugin tracing", Ordered, func() {
+	var app *app.Application
+	var server *http.Server
+	received := make(chan bool, 1)
+	data := `{"message": "` + strings.Repeat("a", 2048) + `"}`
+
+	BeforeAll(func() {
+		helper.InitDB(true, &helper.TestEntities{
+			Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+				o.Request.URL = "http://localhost:9991"
+				o.Request.Compression = &entities.Compression{
+					Algorithm: compression.Gzip,
+					MinSize:   1024,
+					Stage:     entities.CompressionStageBeforeSigning,
+				}
+			}, factory.WithEndpointPlugins(factory.Plugin("webhookx-signature",
+				factory.WithPluginConfig(webhookx_signature.Config{SigningSecret: "secret"}))))},
+			Sources: []*entities.Source{factory.Source()},
+		})
+
+		server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			// the signature covers the compressed body
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(r.Header.Get("webhookx-timestamp") + "."))
+			mac.Write(b)
+			received <- r.Header.Get("webhookx-signature") == "v1="+hex.EncodeToString(mac.Sum(nil))
+			w.WriteHeader(200)
+		}, ":9991")
+
+		app = helper.MustStart(map[string]string{
+			"WEBHOOKX_TRACING_INSTRUMENTATIONS": "@all",
+			"WEBHOOKX_TRACING_SAMPLING_RATE":    "1.0",
+		})
+		err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+		assert.NoError(GinkgoT(), err)
+	})
+
+	AfterAll(func() {
+		app.Stop()
+		_ = server.Shutdown(context.TODO())
+	})
+
+	It("signs the compressed body", func() {
+		resp, err := helper.ProxyClient().R().
+			SetBody(`{"event_type": "foo.bar","data": ` + data + `}`).
+			Post("/")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+		select {
+		case verified := <-received:
+			assert.True(GinkgoT(), verified)
+		case <-time.After(time.Second * 10):
+			Fail("no request received")
+		}
+	})
+})
@@ -0,0 +1,83 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+)
+
+var _ = Describe("max_concurrency", Ordered, func() {
+	Context("endpoint with max_concurrency", func() {
+		var proxyClient *resty.Client
+		var app *app.Application
+		var db *db.DB
+		var server *http.Server
+
+		var inflight atomic.Int32
+		var maxInflight atomic.Int32
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+					o.Request.URL = "http://localhost:9997"
+					o.MaxConcurrency = new(1)
+				})},
+				Sources: []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				n := inflight.Add(1)
+				defer inflight.Add(-1)
+				for {
+					m := maxInflight.Load()
+					if n <= m || maxInflight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond * 200)
+				w.WriteHeader(200)
+			}, ":9997")
+
+			app = helper.MustStart(map[string]string{})
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("in-flight requests should not exceed the limit", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			for i := 0; i < 5; i++ {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				q := dao.AttemptQuery{Status: new(entities.AttemptStatusSuccess)}
+				n, err := db.Attempts.Count(context.TODO(), q.ToQuery())
+				assert.NoError(GinkgoT(), err)
+				return n == 5
+			}, time.Second*15, time.Millisecond*100)
+
+			assert.EqualValues(GinkgoT(), 1, maxInflight.Load())
+		})
+	})
+})
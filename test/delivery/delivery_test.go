@@ -161,6 +161,11 @@ var _ = Describe("delivery", Ordered, func() {
 				assert.Equal(GinkgoT(), i+1, e.AttemptNumber)
 
 				assert.Equal(GinkgoT(), i+1 == len(attempts), e.Exhausted) // exhausted should be true when it's the last attempt
+				if e.Exhausted {
+					assert.Equal(GinkgoT(), entities.AttemptExhaustedReasonMaxAttempts, *e.ExhaustedReason)
+				} else {
+					assert.Nil(GinkgoT(), e.ExhaustedReason)
+				}
 				if i == 0 {
 					assert.Equal(GinkgoT(), entities.AttemptTriggerModeInitial, e.TriggerMode)
 				} else {
@@ -176,6 +181,71 @@ var _ = Describe("delivery", Ordered, func() {
 		})
 	})
 
+	Context("retries (retry policy)", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		BeforeAll(func() {
+			entitiesConfig := helper.TestEntities{
+				Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+					o.Request.URL = "http://localhost:9999/status/400"
+					o.Retry.Config.Attempts = []int64{0, 0, 0}
+					o.Retry.Policy = &entities.RetryPolicy{
+						StatusCodes: []string{"429", "5xx"},
+						ErrorCodes:  []entities.AttemptErrorCode{entities.AttemptErrorCodeTimeout},
+					}
+				})},
+				Sources: []*entities.Source{factory.Source()},
+			}
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(nil))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("non-retryable status code exhausts immediately", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			eventId := resp.Header().Get(constants.HeaderEventId)
+
+			time.Sleep(time.Second * 3)
+
+			q := dao.AttemptQuery{}
+			q.EventId = &eventId
+			attempts, err := db.Attempts.List(context.TODO(), q.ToQuery())
+			assert.NoError(GinkgoT(), err)
+			assert.EqualValues(GinkgoT(), 1, len(attempts))
+			attempt := attempts[0]
+			assert.Equal(GinkgoT(), entities.AttemptStatusFailure, attempt.Status)
+			assert.Equal(GinkgoT(), 400, attempt.Response.Status)
+			assert.True(GinkgoT(), attempt.Exhausted)
+			assert.Equal(GinkgoT(), entities.AttemptExhaustedReasonNonRetryableStatus, *attempt.ExhaustedReason)
+			assert.Nil(GinkgoT(), attempt.RetryDecision)
+
+			dq := dao.DeadLetterQuery{}
+			dq.EventId = &eventId
+			deadLetters, err := db.DeadLetters.List(context.TODO(), dq.ToQuery())
+			assert.NoError(GinkgoT(), err)
+			assert.EqualValues(GinkgoT(), 1, len(deadLetters))
+			assert.Equal(GinkgoT(), attempt.ID, deadLetters[0].AttemptId)
+			assert.Equal(GinkgoT(), attempt.EndpointId, deadLetters[0].EndpointId)
+			assert.Equal(GinkgoT(), "foo.bar", deadLetters[0].EventType)
+			assert.Equal(GinkgoT(), entities.AttemptExhaustedReasonNonRetryableStatus, deadLetters[0].Reason)
+		})
+	})
+
 	Context("retries (endpoint disabled)", func() {
 		var proxyClient *resty.Client
 
@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+)
+
+var _ = Describe("filter", Ordered, func() {
+	Context("endpoint with filter", func() {
+		var proxyClient *resty.Client
+		var app *app.Application
+		var db *db.DB
+		var server *http.Server
+
+		var mux sync.Mutex
+		var received []int
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+					o.Request.URL = "http://localhost:9998"
+					o.Filter = new(`data.amount > 100 && data.currency === "USD"`)
+				})},
+				Sources: []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				var data struct {
+					Amount int `json:"amount"`
+				}
+				_ = json.Unmarshal(b, &data)
+
+				mux.Lock()
+				defer mux.Unlock()
+				received = append(received, data.Amount)
+				w.WriteHeader(200)
+			}, ":9998")
+
+			app = helper.MustStart(map[string]string{})
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("only delivers the events accepted by filter", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			events := []string{
+				`{"amount": 50, "currency": "USD"}`,
+				`{"amount": 200, "currency": "EUR"}`,
+				`{"amount": 300, "currency": "USD"}`,
+			}
+			for _, data := range events {
+				resp, err := proxyClient.R().
+					SetBody(fmt.Sprintf(`{"event_type": "foo.bar","data": %s}`, data)).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				mux.Lock()
+				defer mux.Unlock()
+				return len(received) == 1
+			}, time.Second*5, time.Millisecond*100)
+			assert.Equal(GinkgoT(), []int{300}, received)
+
+			// the rejected events are persisted without attempts
+			assert.Eventually(GinkgoT(), func() bool {
+				n, err := db.Events.Count(context.TODO(), &dao.Query{})
+				return err == nil && n == 3
+			}, time.Second*5, time.Millisecond*100)
+			n, err := db.Attempts.Count(context.TODO(), &dao.Query{})
+			assert.NoError(GinkgoT(), err)
+			assert.EqualValues(GinkgoT(), 1, n)
+		})
+	})
+})
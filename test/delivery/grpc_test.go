@@ -0,0 +1,89 @@
+package delivery
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/worker/deliverer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+var _ = Describe("grpc", Ordered, func() {
+	var app *app.Application
+	var db *db.DB
+	var server *grpc.Server
+
+	BeforeAll(func() {
+		listener, err := net.Listen("tcp", "localhost:9992")
+		assert.NoError(GinkgoT(), err)
+		server = grpc.NewServer()
+		healthServer := health.NewServer()
+		healthServer.SetServingStatus("webhookx", healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(server, healthServer)
+		reflection.Register(server)
+		go func() { _ = server.Serve(listener) }()
+
+		db = helper.InitDB(true, &helper.TestEntities{
+			Endpoints: []*entities.Endpoint{
+				factory.Endpoint(func(o *entities.Endpoint) {
+					o.Type = deliverer.EndpointTypeGRPC
+					o.Request.URL = "grpc://localhost:9992"
+					o.Events = []string{"service.serving"}
+					o.Config = entities.EndpointConfig{
+						"grpc": {"method": "grpc.health.v1.Health/Check"},
+					}
+				}),
+				factory.Endpoint(func(o *entities.Endpoint) {
+					o.Type = deliverer.EndpointTypeGRPC
+					o.Request.URL = "grpc://localhost:9992"
+					o.Events = []string{"service.unknown"}
+					o.Retry.Config.Attempts = []int64{0}
+					o.Config = entities.EndpointConfig{
+						"grpc": {"method": "grpc.health.v1.Health/Check"},
+					}
+				}),
+			},
+			Sources: []*entities.Source{factory.Source()},
+		})
+		app = helper.MustStart(map[string]string{})
+		err = helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+		assert.NoError(GinkgoT(), err)
+	})
+
+	AfterAll(func() {
+		app.Stop()
+		server.Stop()
+	})
+
+	It("calls the method with event mapped into request message", func() {
+		resp, err := helper.ProxyClient().R().
+			SetBody(`{"event_type": "service.serving","data": {"service": "webhookx"}}`).
+			Post("/")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+		attempt := waitForAttempt(db, entities.AttemptStatusSuccess)
+		assert.Equal(GinkgoT(), 200, attempt.Response.Status)
+	})
+
+	It("fails the attempt with mapped status code", func() {
+		resp, err := helper.ProxyClient().R().
+			SetBody(`{"event_type": "service.unknown","data": {"service": "unknown"}}`).
+			Post("/")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+		attempt := waitForAttempt(db, entities.AttemptStatusFailure)
+		assert.Equal(GinkgoT(), 404, attempt.Response.Status)
+	})
+})
@@ -0,0 +1,162 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/plugins/function"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+)
+
+var _ = Describe("ordering", Ordered, func() {
+	Context("ordered endpoint", func() {
+		var proxyClient *resty.Client
+		var app *app.Application
+		var server *http.Server
+
+		var mux sync.Mutex
+		var received []int
+		failed := false
+
+		BeforeAll(func() {
+			helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+					o.Request.URL = "http://localhost:9998"
+					o.Retry.Config.Attempts = []int64{0, 1}
+					o.Ordering = &entities.Ordering{Key: new("/customer")}
+				})},
+				Sources: []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				var data struct {
+					N int `json:"n"`
+				}
+				_ = json.Unmarshal(b, &data)
+
+				mux.Lock()
+				defer mux.Unlock()
+				received = append(received, data.N)
+				// the first delivery of the first event fails
+				if data.N == 1 && !failed {
+					failed = true
+					w.WriteHeader(500)
+					return
+				}
+				w.WriteHeader(200)
+			}, ":9998")
+
+			app = helper.MustStart(map[string]string{})
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("later events wait behind retries of earlier ones", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			for i := 1; i <= 5; i++ {
+				resp, err := proxyClient.R().
+					SetBody(fmt.Sprintf(`{"event_type": "foo.bar","data": {"n": %d, "customer": "c1"}}`, i)).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+				time.Sleep(time.Millisecond * 10)
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				mux.Lock()
+				defer mux.Unlock()
+				return len(received) == 6
+			}, time.Second*15, time.Millisecond*100)
+
+			assert.Equal(GinkgoT(), []int{1, 1, 2, 3, 4, 5}, received)
+		})
+	})
+
+	Context("head delivery fails permanently", func() {
+		var proxyClient *resty.Client
+		var app *app.Application
+		var server *http.Server
+
+		var mux sync.Mutex
+		var received []int
+
+		BeforeAll(func() {
+			endpoint := factory.Endpoint(func(o *entities.Endpoint) {
+				o.Request.URL = "http://localhost:9998"
+				o.Retry.Config.Attempts = []int64{0, 1}
+				o.Ordering = &entities.Ordering{Key: new("/customer")}
+			})
+			// the plugin always fails on the first event
+			endpoint.Plugins = []*entities.Plugin{
+				factory.Plugin("function", factory.WithPluginConfig(function.Config{
+					Function: `function handle() { if (JSON.parse(webhookx.event.getData()).n === 1) { throw("failed") } }`,
+				})),
+			}
+			helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{endpoint},
+				Sources:   []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				var data struct {
+					N int `json:"n"`
+				}
+				_ = json.Unmarshal(b, &data)
+
+				mux.Lock()
+				defer mux.Unlock()
+				received = append(received, data.N)
+				w.WriteHeader(200)
+			}, ":9998")
+
+			app = helper.MustStart(map[string]string{})
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("later events are delivered once the head is exhausted", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			for i := 1; i <= 3; i++ {
+				resp, err := proxyClient.R().
+					SetBody(fmt.Sprintf(`{"event_type": "foo.bar","data": {"n": %d, "customer": "c1"}}`, i)).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+				time.Sleep(time.Millisecond * 10)
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				mux.Lock()
+				defer mux.Unlock()
+				return len(received) == 2
+			}, time.Second*15, time.Millisecond*100)
+
+			assert.Equal(GinkgoT(), []int{2, 3}, received)
+		})
+	})
+})
@@ -0,0 +1,94 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/worker"
+)
+
+var _ = Describe("system events", Ordered, func() {
+	Context("webhookx.attempt.exhausted", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+		var endpoint = factory.Endpoint(func(o *entities.Endpoint) {
+			o.Request.URL = "http://localhost:9999/status/400"
+			o.Retry.Config.Attempts = []int64{0}
+		})
+		var alertEndpoint *entities.Endpoint
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{endpoint},
+				Sources:   []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			ws := factory.Workspace("system")
+			assert.NoError(GinkgoT(), db.Workspaces.Insert(context.TODO(), ws))
+			alertEndpoint = factory.EndpointWS(ws.ID, func(o *entities.Endpoint) {
+				o.Events = []string{worker.EventTypeAttemptExhausted}
+			})
+			assert.NoError(GinkgoT(), db.Endpoints.Insert(context.TODO(), alertEndpoint))
+
+			app = helper.MustStart(map[string]string{
+				"WEBHOOKX_WORKER_SYSTEM_EVENTS_ENABLED":   "true",
+				"WEBHOOKX_WORKER_SYSTEM_EVENTS_WORKSPACE": "system",
+			})
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("should be dispatched to the system workspace", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			eventId := resp.Header().Get(constants.HeaderEventId)
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				q := dao.AttemptQuery{EndpointId: &alertEndpoint.ID}
+				attempts, err := db.Attempts.List(context.TODO(), q.ToQuery())
+				assert.NoError(GinkgoT(), err)
+				if len(attempts) == 1 && attempts[0].Status == entities.AttemptStatusSuccess {
+					attempt = attempts[0]
+					return true
+				}
+				return false
+			}, time.Second*5, time.Millisecond*100)
+
+			event, err := db.Events.Get(context.TODO(), attempt.EventId)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), worker.EventTypeAttemptExhausted, event.EventType)
+			assert.Equal(GinkgoT(), alertEndpoint.WorkspaceId, event.WorkspaceId)
+
+			var data worker.AttemptExhaustedData
+			assert.NoError(GinkgoT(), json.Unmarshal(event.Data, &data))
+			assert.Equal(GinkgoT(), endpoint.ID, data.Endpoint.ID)
+			assert.Equal(GinkgoT(), eventId, data.EventId)
+			assert.Equal(GinkgoT(), "foo.bar", data.EventType)
+			assert.Equal(GinkgoT(), entities.AttemptExhaustedReasonMaxAttempts, data.Reason)
+			assert.NotEmpty(GinkgoT(), data.DeadLetterId)
+		})
+	})
+})
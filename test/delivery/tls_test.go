@@ -0,0 +1,48 @@
+package delivery
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+)
+
+var _ = Describe("tls", Ordered, func() {
+	var app *app.Application
+	var db *db.DB
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, &helper.TestEntities{
+			Endpoints: []*entities.Endpoint{factory.Endpoint(func(o *entities.Endpoint) {
+				o.Request.URL = "https://localhost:9999"
+				o.Request.TLS = &entities.TLS{CACert: new("{secret://vault/webhookx/mtls.ca}")}
+			})},
+			Sources: []*entities.Source{factory.Source()},
+		})
+
+		app = helper.MustStart(map[string]string{})
+		err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+		assert.NoError(GinkgoT(), err)
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	It("records a failed attempt when secret reference cannot be resolved", func() {
+		resp, err := helper.ProxyClient().R().
+			SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+			Post("/")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+		attempt := waitForAttempt(db, entities.AttemptStatusFailure)
+		assert.Equal(GinkgoT(), entities.AttemptErrorCodeUnknown, *attempt.ErrorCode)
+		assert.Nil(GinkgoT(), attempt.Response)
+	})
+})
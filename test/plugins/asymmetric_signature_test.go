@@ -0,0 +1,114 @@
+package plugins
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/jwk"
+	"github.com/webhookx-io/webhookx/plugins/asymmetric_signature"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("asymmetric-signature", Ordered, func() {
+
+	Context("sanity", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		endpoint := factory.Endpoint()
+		endpoint.Plugins = []*entities.Plugin{
+			factory.Plugin("asymmetric-signature",
+				factory.WithPluginConfig(asymmetric_signature.Config{
+					Keys: []asymmetric_signature.Key{
+						{Id: "key-1", PrivateKey: utils.Must(asymmetric_signature.GenerateKey())},
+						{Id: "key-2", PrivateKey: utils.Must(asymmetric_signature.GenerateKey())},
+					},
+					Format: asymmetric_signature.FormatJWS,
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{endpoint},
+				Sources:   []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(nil))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("signature should be verifiable with the published JWKS", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			resp, err := proxyClient.R().
+				SetResult(jwk.Set{}).
+				Get("/.well-known/webhookx/jwks.json")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			set := resp.Result().(*jwk.Set)
+			assert.Len(GinkgoT(), set.Keys, 2)
+			keys := make(map[string]jwk.Key)
+			for _, key := range set.Keys {
+				keys[key.Kid] = key
+			}
+			assert.Equal(GinkgoT(), "EdDSA", keys["key-1"].Alg)
+			assert.Equal(GinkgoT(), "EdDSA", keys["key-2"].Alg)
+
+			resp, err = proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				q := dao.AttemptQuery{Status: new(entities.AttemptStatusSuccess)}
+				list, err := db.Attempts.List(context.TODO(), q.ToQuery())
+				if err != nil || len(list) != 1 {
+					return false
+				}
+				attempt = list[0]
+				return true
+			}, time.Second*5, time.Millisecond*100)
+
+			var detail *entities.AttemptDetail
+			assert.Eventually(GinkgoT(), func() bool {
+				detail, err = db.AttemptDetails.Get(context.TODO(), attempt.ID)
+				return err == nil && detail != nil
+			}, time.Second*5, time.Millisecond*100)
+
+			parts := strings.Split(detail.RequestHeaders["Webhookx-Jws-Signature"], ".")
+			assert.Len(GinkgoT(), parts, 3)
+			header, err := base64.RawURLEncoding.DecodeString(parts[0])
+			assert.NoError(GinkgoT(), err)
+			assert.Contains(GinkgoT(), string(header), `"kid":"key-1"`)
+
+			x, err := base64.RawURLEncoding.DecodeString(keys["key-1"].X)
+			assert.NoError(GinkgoT(), err)
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			assert.NoError(GinkgoT(), err)
+			message := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(*detail.RequestBody))
+			assert.True(GinkgoT(), ed25519.Verify(x, []byte(message), signature))
+		})
+	})
+})
@@ -33,6 +33,19 @@ function handle() {
 }
 `
 
+var function_outbound = `
+function handle() {
+	if (webhookx.event.getType() === 'foo.skip') {
+		webhookx.delivery.skip()
+		return
+	}
+	var obj = JSON.parse(webhookx.request.getBody())
+	obj.type = webhookx.event.getType()
+	webhookx.request.setBody(JSON.stringify(obj))
+	webhookx.request.setHeader('X-Event-Type', webhookx.event.getType())
+}
+`
+
 var _ = Describe("function", Ordered, func() {
 
 	Context("sanity", func() {
@@ -103,4 +116,81 @@ var _ = Describe("function", Ordered, func() {
 			}, time.Second*5, time.Second)
 		})
 	})
+
+	Context("outbound", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		endpoint := factory.Endpoint(func(o *entities.Endpoint) {
+			o.Events = []string{"foo.bar", "foo.skip"}
+		})
+		endpoint.Plugins = []*entities.Plugin{factory.Plugin("function",
+			func(o *entities.Plugin) {
+				o.Config = map[string]interface{}{
+					"function": function_outbound,
+				}
+			})}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{endpoint},
+				Sources:   []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(nil))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("should transform request and skip delivery", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			for _, eventType := range []string{"foo.bar", "foo.skip"} {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "` + eventType + `","data": {"key": "value"}}`).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			}
+
+			var attempts []*entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &dao.Query{})
+				if err != nil || len(list) != 2 {
+					return false
+				}
+				for _, attempt := range list {
+					if attempt.Status != entities.AttemptStatusSuccess && attempt.Status != entities.AttemptStatusCanceled {
+						return false
+					}
+				}
+				attempts = list
+				return true
+			}, time.Second*10, time.Millisecond*200)
+
+			for _, attempt := range attempts {
+				event, err := db.Events.Get(context.TODO(), attempt.EventId)
+				assert.NoError(GinkgoT(), err)
+				if event.EventType == "foo.skip" {
+					assert.Equal(GinkgoT(), entities.AttemptStatusCanceled, attempt.Status)
+					assert.Equal(GinkgoT(), entities.AttemptErrorCodeFiltered, *attempt.ErrorCode)
+					continue
+				}
+				assert.Equal(GinkgoT(), entities.AttemptStatusSuccess, attempt.Status)
+				var detail *entities.AttemptDetail
+				assert.Eventually(GinkgoT(), func() bool {
+					detail, err = db.AttemptDetails.Get(context.TODO(), attempt.ID)
+					return err == nil && detail != nil
+				}, time.Second*5, time.Millisecond*100)
+				assert.Equal(GinkgoT(), "foo.bar", detail.RequestHeaders["X-Event-Type"])
+				assert.JSONEq(GinkgoT(), `{"type": "foo.bar", "key": "value"}`, *detail.RequestBody)
+			}
+		})
+	})
 })
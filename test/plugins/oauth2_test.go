@@ -0,0 +1,185 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/plugins/oauth2"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("oauth2", Ordered, func() {
+
+	Context("sanity", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+		var tokenServer *http.Server
+		var server *http.Server
+
+		var issued atomic.Int32
+		var mux sync.Mutex
+		var authorizations []string
+
+		endpoint := factory.Endpoint(func(o *entities.Endpoint) {
+			o.Request.URL = "http://localhost:9995"
+		})
+		endpoint.Plugins = []*entities.Plugin{
+			factory.Plugin("oauth2",
+				factory.WithPluginConfig(oauth2.Config{
+					TokenURL:     "http://localhost:9994/oauth/token",
+					ClientId:     "client",
+					ClientSecret: "secret",
+					Scopes:       []string{"webhooks"},
+					AuthMethod:   oauth2.AuthMethodClientSecretBasic,
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{endpoint},
+				Sources:   []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			tokenServer = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				n := issued.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+			}, ":9994")
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				mux.Lock()
+				defer mux.Unlock()
+				authorization := r.Header.Get("Authorization")
+				authorizations = append(authorizations, authorization)
+				// the first token is revoked
+				if authorization == "Bearer token-1" {
+					w.WriteHeader(401)
+					return
+				}
+				w.WriteHeader(200)
+			}, ":9995")
+
+			app = utils.Must(helper.Start(nil))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = tokenServer.Shutdown(context.TODO())
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("should refresh token and deliver again on 401", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				q := dao.AttemptQuery{Status: new(entities.AttemptStatusSuccess)}
+				list, err := db.Attempts.List(context.TODO(), q.ToQuery())
+				if err != nil || len(list) != 1 {
+					return false
+				}
+				attempt = list[0]
+				return true
+			}, time.Second*5, time.Millisecond*100)
+			assert.EqualValues(GinkgoT(), 1, attempt.AttemptNumber)
+
+			mux.Lock()
+			defer mux.Unlock()
+			assert.Equal(GinkgoT(), []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+		})
+	})
+
+	Context("token request failed", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+		var tokenServer *http.Server
+
+		endpoint := factory.Endpoint(func(o *entities.Endpoint) {
+			o.Request.URL = "http://localhost:9995"
+		})
+		endpoint.Plugins = []*entities.Plugin{
+			factory.Plugin("oauth2",
+				factory.WithPluginConfig(oauth2.Config{
+					TokenURL:     "http://localhost:9994/oauth/token",
+					ClientId:     "client",
+					ClientSecret: "secret",
+					AuthMethod:   oauth2.AuthMethodClientSecretBasic,
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{endpoint},
+				Sources:   []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			tokenServer = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(500)
+			}, ":9994")
+
+			app = utils.Must(helper.Start(nil))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = tokenServer.Shutdown(context.TODO())
+		})
+
+		It("should record a failed attempt", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				q := dao.AttemptQuery{Status: new(entities.AttemptStatusFailure)}
+				list, err := db.Attempts.List(context.TODO(), q.ToQuery())
+				if err != nil {
+					return false
+				}
+				for _, e := range list {
+					if e.AttemptNumber == 1 {
+						attempt = e
+						return true
+					}
+				}
+				return false
+			}, time.Second*5, time.Millisecond*100)
+			assert.Equal(GinkgoT(), entities.AttemptErrorCodeUnknown, *attempt.ErrorCode)
+			assert.Nil(GinkgoT(), attempt.Response)
+		})
+	})
+})
@@ -0,0 +1,94 @@
+package plugins
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/plugins/transform"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("transform", Ordered, func() {
+
+	Context("sanity", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var server *http.Server
+
+		var mux sync.Mutex
+		var received *http.Request
+		var body []byte
+
+		endpoint := factory.Endpoint(func(o *entities.Endpoint) {
+			o.Request.URL = "http://localhost:9993/hooks"
+		})
+		endpoint.Plugins = []*entities.Plugin{
+			factory.Plugin("transform",
+				factory.WithPluginConfig(transform.Config{
+					Body:    `{"text": {{ json (printf "%s: %s" .type .data.key) }}}`,
+					Headers: map[string]string{"X-Event-Type": "{{ .type }}"},
+					Path:    "/services/{{ .id }}",
+					Query:   map[string]string{"type": "{{ .type }}"},
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			helper.InitDB(true, &helper.TestEntities{
+				Endpoints: []*entities.Endpoint{endpoint},
+				Sources:   []*entities.Source{factory.Source()},
+			})
+			proxyClient = helper.ProxyClient()
+
+			server = helper.StartHttpServer(func(w http.ResponseWriter, r *http.Request) {
+				mux.Lock()
+				defer mux.Unlock()
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(200)
+			}, ":9993")
+
+			app = utils.Must(helper.Start(nil))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+			_ = server.Shutdown(context.TODO())
+		})
+
+		It("should deliver the transformed request", func() {
+			err := helper.WaitForServer(helper.ProxyHttpURL, time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			assert.Eventually(GinkgoT(), func() bool {
+				mux.Lock()
+				defer mux.Unlock()
+				return received != nil
+			}, time.Second*5, time.Millisecond*100)
+
+			mux.Lock()
+			defer mux.Unlock()
+			assert.Equal(GinkgoT(), `{"text": "foo.bar: value"}`, string(body))
+			assert.Equal(GinkgoT(), "foo.bar", received.Header.Get("X-Event-Type"))
+			assert.Equal(GinkgoT(), "foo.bar", received.URL.Query().Get("type"))
+			assert.Equal(GinkgoT(), "/services/"+received.Header.Get("Webhookx-Event-Id"), received.URL.Path)
+		})
+	})
+})
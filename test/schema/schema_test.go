@@ -58,7 +58,22 @@ var _ = Describe("schemas", Ordered, func() {
 							"strategy": "unknown",
 						},
 					},
-					feildsJSON: `{"retry":{"strategy":"value is not one of the allowed values [\"fixed\"]"}}`,
+					feildsJSON: `{"retry":{"strategy":"value is not one of the allowed values [\"fixed\",\"exponential\"]"}}`,
+				},
+				{
+					name: "unknown retry.config.jitter",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"retry": map[string]interface{}{
+							"strategy": "exponential",
+							"config": map[string]interface{}{
+								"jitter": "random",
+							},
+						},
+					},
+					feildsJSON: `{"retry":{"config":{"jitter":"value is not one of the allowed values [\"none\",\"full\",\"equal\"]"}}}`,
 				},
 				{
 					name: "retry.config.attempts is empty list",
@@ -90,6 +105,101 @@ var _ = Describe("schemas", Ordered, func() {
 					},
 					feildsJSON: `{"retry":{"config":{"attempts":[null,"value must be an integer","value must be an integer"]}}}`,
 				},
+				{
+					name: "retry.config.multiplier is less than 1",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"retry": map[string]interface{}{
+							"strategy": "exponential",
+							"config": map[string]interface{}{
+								"multiplier": 0.5,
+							},
+						},
+					},
+					feildsJSON: `{"retry":{"config":{"multiplier":"number must be at least 1"}}}`,
+				},
+				{
+					name: "retry.retry_after.max_delay is invalid",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"retry": map[string]interface{}{
+							"retry_after": map[string]interface{}{
+								"max_delay": 0,
+							},
+						},
+					},
+					feildsJSON: `{"retry":{"retry_after":{"max_delay":"number must be at least 1"}}}`,
+				},
+				{
+					name: "retry.policy is invalid",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"retry": map[string]interface{}{
+							"policy": map[string]interface{}{
+								"status_codes": []interface{}{"5xx", "600"},
+								"error_codes":  []interface{}{"DENIED"},
+							},
+						},
+					},
+					feildsJSON: `{"retry":{"policy":{"error_codes":["value is not one of the allowed values [\"TIMEOUT\",\"UNKNOWN\",\"BROKER_UNAVAILABLE\",\"AUTH_FAILED\"]"],"status_codes":[null,"string doesn't match the regular expression \"^([1-5]xx|[1-5][0-9]{2}(-[1-5][0-9]{2})?)$\""]}}}`,
+				},
+				{
+					name: "circuit_breaker is invalid",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"circuit_breaker": map[string]interface{}{
+							"window_size":               10,
+							"failure_rate_threshold":    101,
+							"minimum_request_threshold": 0,
+						},
+					},
+					feildsJSON: `{"circuit_breaker":{"failure_rate_threshold":"number must be at most 100","minimum_request_threshold":"number must be at least 1","window_size":"number must be at least 60"}}`,
+				},
+				{
+					name: "max_concurrency is invalid",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"max_concurrency": 0,
+					},
+					feildsJSON: `{"max_concurrency":"number must be at least 1"}`,
+				},
+				{
+					name: "ordering.key is invalid",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"ordering": map[string]interface{}{
+							"key": "customer.id",
+						},
+					},
+					feildsJSON: `{"ordering":{"key":"string doesn't match the regular expression \"^/\""}}`,
+				},
+				{
+					name: "delivery is invalid",
+					data: map[string]interface{}{
+						"request": map[string]interface{}{
+							"url": "http://example.com",
+						},
+						"delivery": map[string]interface{}{
+							"mode":       "stream",
+							"max_events": 0,
+							"max_bytes":  1,
+							"max_wait":   60000,
+						},
+					},
+					feildsJSON: `{"delivery":{"max_bytes":"number must be at least 1024","max_events":"number must be at least 1","max_wait":"number must be at most 30000","mode":"value is not one of the allowed values [\"single\",\"batch\"]"}}`,
+				},
 			}
 			for _, test := range tests {
 				err := openapi.Validate(schema, test.data)
@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/worker/ordering"
+)
+
+var _ = Describe("sequencer", Ordered, func() {
+	var client *redis.Client
+	var sequencer *ordering.Sequencer
+	var endpoint *entities.Endpoint
+
+	BeforeAll(func() {
+		cfg, err := helper.LoadConfig(helper.LoadConfigOptions{
+			Envs: helper.NewTestEnv(nil),
+		})
+		assert.NoError(GinkgoT(), err)
+		client = cfg.Redis.GetClient()
+		sequencer = ordering.NewSequencer(client)
+	})
+
+	BeforeEach(func() {
+		assert.NoError(GinkgoT(), client.FlushDB(context.TODO()).Err())
+		endpoint = factory.Endpoint(func(o *entities.Endpoint) {
+			o.Ordering = &entities.Ordering{}
+		})
+	})
+
+	It("sequences events in the order they are enqueued", func() {
+		ctx := context.TODO()
+		e1 := factory.Event()
+		e1.IngestedAt = types.NewTime(time.Now())
+		// a replayed event that was ingested earlier
+		e2 := factory.Event()
+		e2.IngestedAt = types.NewTime(time.Now().Add(-time.Hour))
+
+		assert.NoError(GinkgoT(), sequencer.Enqueue(ctx, endpoint, e1))
+		assert.NoError(GinkgoT(), sequencer.Enqueue(ctx, endpoint, e2))
+
+		ok, err := sequencer.Acquire(ctx, endpoint, "", e2.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.False(GinkgoT(), ok)
+		ok, err = sequencer.Acquire(ctx, endpoint, "", e1.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.True(GinkgoT(), ok)
+
+		assert.NoError(GinkgoT(), sequencer.Release(ctx, endpoint, "", e1.ID))
+		ok, err = sequencer.Acquire(ctx, endpoint, "", e2.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.True(GinkgoT(), ok)
+	})
+
+	It("evicts the head whose lease expired", func() {
+		ctx := context.TODO()
+		e1, e2 := factory.Event(), factory.Event()
+		assert.NoError(GinkgoT(), sequencer.Enqueue(ctx, endpoint, e1))
+		assert.NoError(GinkgoT(), sequencer.Enqueue(ctx, endpoint, e2))
+
+		ok, err := sequencer.Acquire(ctx, endpoint, "", e2.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.False(GinkgoT(), ok)
+
+		// the sequence is not extended by the waiters
+		ttl := client.PTTL(ctx, "webhookx:ordering:v1:"+endpoint.ID+":").Val()
+		time.Sleep(time.Millisecond * 100)
+		ok, err = sequencer.Acquire(ctx, endpoint, "", e2.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.False(GinkgoT(), ok)
+		assert.Less(GinkgoT(), client.PTTL(ctx, "webhookx:ordering:v1:"+endpoint.ID+":").Val(), ttl)
+
+		// simulates the lease of head expired
+		assert.NoError(GinkgoT(), client.Del(ctx, "webhookx:ordering_head:v1:"+endpoint.ID+":").Err())
+		ok, err = sequencer.Acquire(ctx, endpoint, "", e2.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.True(GinkgoT(), ok)
+	})
+
+	It("removes the event by its id", func() {
+		ctx := context.TODO()
+		endpoint.Ordering.Key = new("/customer")
+		e1 := factory.Event(func(o *entities.Event) { o.Data = []byte(`{"customer": "c1"}`) })
+		e2 := factory.Event(func(o *entities.Event) { o.Data = []byte(`{"customer": "c1"}`) })
+		assert.NoError(GinkgoT(), sequencer.Enqueue(ctx, endpoint, e1))
+		assert.NoError(GinkgoT(), sequencer.Enqueue(ctx, endpoint, e2))
+
+		assert.NoError(GinkgoT(), sequencer.Remove(ctx, endpoint, e1.ID))
+		ok, err := sequencer.Acquire(ctx, endpoint, "c1", e2.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.True(GinkgoT(), ok)
+	})
+})
@@ -2,7 +2,9 @@ package utils
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func HeaderMap(header http.Header) map[string]string {
@@ -12,3 +14,22 @@ func HeaderMap(header http.Header) map[string]string {
 	}
 	return headers
 }
+
+// ParseRetryAfter parses the value of Retry-After header that is either a delta-seconds or an HTTP-date.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "120", expected: time.Second * 120, ok: true},
+		{value: " 0 ", expected: 0, ok: true},
+		{value: "Sat, 17 Oct 2026 08:05:00 GMT", expected: time.Minute * 5, ok: true},
+		{value: "Sat, 17 Oct 2026 07:00:00 GMT", expected: 0, ok: true},
+		{value: "-1", ok: false},
+		{value: "", ok: false},
+		{value: "tomorrow", ok: false},
+	}
+	for _, test := range tests {
+		d, ok := ParseRetryAfter(test.value, now)
+		assert.Equal(t, test.ok, ok, test.value)
+		assert.Equal(t, test.expected, d, test.value)
+	}
+}
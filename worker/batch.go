@@ -0,0 +1,297 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/taskqueue"
+	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"github.com/webhookx-io/webhookx/plugins"
+	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/circuitbreaker"
+	"github.com/webhookx-io/webhookx/worker/deliverer"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Batch is a group of tasks of the same endpoint and attempt number that are delivered in one request,
+// the attempts of a failed batch are retried at the same time so that they are batched again.
+type Batch struct {
+	ID       string
+	Endpoint *entities.Endpoint
+	Tasks    []*taskqueue.TaskMessage
+
+	size  int
+	timer *time.Timer
+}
+
+// BatchItem is the element of the JSON array in the batch request body
+type BatchItem struct {
+	EventId    string          `json:"event_id"`
+	DeliveryId string          `json:"delivery_id"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// batcher coalesces the tasks of batch-mode endpoints in memory, a batch is flushed when
+// max_events or max_bytes is reached, or max_wait elapsed since the first task was added.
+type batcher struct {
+	mux     sync.Mutex
+	batches map[string]*Batch
+	flush   func(*Batch)
+	wg      sync.WaitGroup
+}
+
+func newBatcher(flush func(*Batch)) *batcher {
+	return &batcher{
+		batches: make(map[string]*Batch),
+		flush:   flush,
+	}
+}
+
+func batchKey(endpointId string, attempt int) string {
+	return endpointId + ":" + strconv.Itoa(attempt)
+}
+
+// Add appends the task to the pending batch of endpoint
+func (b *batcher) Add(endpoint *entities.Endpoint, task *taskqueue.TaskMessage) {
+	data := task.Data.(*taskqueue.MessageData)
+	key := batchKey(endpoint.ID, data.Attempt)
+	size := len(data.Event)
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	batch := b.batches[key]
+	if batch != nil && batch.size+size > endpoint.Delivery.MaxBytes {
+		b.take(key, batch)
+		batch = nil
+	}
+	if batch == nil {
+		batch = &Batch{ID: utils.KSUID(), Endpoint: endpoint}
+		wait := time.Duration(endpoint.Delivery.MaxWait) * time.Millisecond
+		batch.timer = time.AfterFunc(wait, func() {
+			b.mux.Lock()
+			defer b.mux.Unlock()
+			if b.batches[key] == batch {
+				b.take(key, batch)
+			}
+		})
+		b.batches[key] = batch
+	}
+	batch.Tasks = append(batch.Tasks, task)
+	batch.size += size
+
+	if len(batch.Tasks) >= endpoint.Delivery.MaxEvents || batch.size >= endpoint.Delivery.MaxBytes {
+		b.take(key, batch)
+	}
+}
+
+// take removes the batch from pending batches and flushes it, the caller must hold the lock
+func (b *batcher) take(key string, batch *Batch) {
+	batch.timer.Stop()
+	delete(b.batches, key)
+	b.wg.Go(func() { b.flush(batch) })
+}
+
+// Close flushes the pending batches and waits for the flushes to finish
+func (b *batcher) Close() {
+	b.mux.Lock()
+	for key, batch := range b.batches {
+		b.take(key, batch)
+	}
+	b.mux.Unlock()
+	b.wg.Wait()
+}
+
+func (w *Worker) flushBatch(batch *Batch) {
+	ctx, span := tracing.Start(context.Background(), "worker.batch.flush")
+	span.SetAttributes(attribute.String("id", batch.ID), attribute.Int("size", len(batch.Tasks)))
+	defer span.End()
+
+	processing.Add(int64(len(batch.Tasks)))
+	defer processing.Add(-int64(len(batch.Tasks)))
+
+	ids := make([]string, 0, len(batch.Tasks))
+	for _, task := range batch.Tasks {
+		ids = append(ids, task.ID)
+	}
+
+	err := w.handleBatch(ctx, batch)
+	if err != nil {
+		if isRescheduled(err) {
+			return
+		}
+		w.log.Errorf("failed to handle batch %s: %v", batch.ID, err)
+		return
+	}
+	if err := w.services.Task.DeleteTasks(ctx, ids); err != nil {
+		w.log.Warnf("failed to delete tasks of batch %s: %v", batch.ID, err)
+	}
+}
+
+// rescheduleBatch holds back the tasks of batch until the time
+func (w *Worker) rescheduleBatch(ctx context.Context, batch *Batch, at time.Time) error {
+	for _, task := range batch.Tasks {
+		task.ScheduledAt = at
+		if err := w.services.Task.ScheduleTask(ctx, task.ID, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) handleBatch(ctx context.Context, batch *Batch) error {
+	endpoint := batch.Endpoint
+
+	// a batch is one delivery request, so it is charged once regardless of its size
+	allowed, next, err := w.allowRateLimit(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		w.log.Debugw("rate limit exceeded", "endpoint", endpoint.ID, "batch", batch.ID, "next", next)
+		if err := w.rescheduleBatch(ctx, batch, next); err != nil {
+			return err
+		}
+		return ErrRateLimitExceeded
+	}
+
+	if endpoint.MaxConcurrency != nil {
+		lease := time.Duration(endpoint.Request.Timeout)*time.Millisecond + concurrencyLeaseMargin
+		ok, err := w.services.Semaphore.Acquire(ctx, endpoint.ID, batch.ID, *endpoint.MaxConcurrency, lease)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			w.log.Debugw("concurrency limit exceeded", "endpoint", endpoint.ID, "batch", batch.ID)
+			if err := w.rescheduleBatch(ctx, batch, time.Now().Add(concurrencyLimitedWait)); err != nil {
+				return err
+			}
+			return ErrConcurrencyLimited
+		}
+		defer func() {
+			if err := w.services.Semaphore.Release(ctx, endpoint.ID, batch.ID); err != nil {
+				w.log.Warnf("failed to release concurrency of endpoint %s: %v", endpoint.ID, err)
+			}
+		}()
+	}
+
+	permit := circuitbreaker.Permit{State: circuitbreaker.StateClosed, Allowed: true}
+	if _, enabled := circuitbreaker.EndpointThresholds(endpoint); enabled {
+		permit, err = w.cbm.Acquire(ctx, endpoint.ID)
+		if err != nil {
+			return err
+		}
+		if !permit.Allowed {
+			w.log.Debugw("circuit breaker is open", "endpoint", endpoint.ID, "state", permit.State, "batch", batch.ID)
+			if err := w.rescheduleBatch(ctx, batch, permit.RetryAt); err != nil {
+				return err
+			}
+			return ErrCircuitBreakerOpen
+		}
+	}
+
+	r, err := newRequestFromEndpoint(endpoint)
+	if err != nil {
+		for _, task := range batch.Tasks {
+			if err := w.db.Attempts.UpdateErrorCode(
+				ctx, task.ID,
+				entities.AttemptStatusCanceled,
+				entities.AttemptErrorCodeUnknown,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	items := make([]BatchItem, 0, len(batch.Tasks))
+	for _, task := range batch.Tasks {
+		data := task.Data.(*taskqueue.MessageData)
+		items = append(items, BatchItem{
+			EventId:    data.EventID,
+			DeliveryId: task.ID,
+			Data:       json.RawMessage(data.Event),
+		})
+	}
+	body, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Webhookx-Batch-Id", batch.ID)
+
+	iterator := plugins.LoadIterator()
+	c := plugin.NewContext(ctx, r, nil)
+	c.SetHTTPClient(w.httpClient)
+	c.SetRequestBody(body)
+	compressor := newCompressor(endpoint)
+	for p := range iterator.Iterate(ctx, plugins.PhaseOutbound, endpoint.ID) {
+		if err := compressor.BeforePlugin(c, p); err != nil {
+			return err
+		}
+		if err := p.ExecuteOutbound(c); err != nil {
+			_, err = w.fail(ctx, endpoint, permit, c.Request,
+				fmt.Errorf("failed to execute %s plugin: %v", p.Name(), err), batch.Tasks...)
+			return err
+		}
+		if c.IsSkipped() {
+			w.log.Debugw("delivery is skipped by plugin", "plugin", p.Name(), "batch", batch.ID)
+			for _, task := range batch.Tasks {
+				err := w.db.Attempts.UpdateErrorCode(ctx, task.ID,
+					entities.AttemptStatusCanceled,
+					entities.AttemptErrorCodeFiltered)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	if err := compressor.AfterPlugins(c); err != nil {
+		return err
+	}
+
+	tlsOptions, err := w.resolveTLS(ctx, endpoint)
+	if err != nil {
+		_, err = w.fail(ctx, endpoint, permit, c.Request, err, batch.Tasks...)
+		return err
+	}
+
+	request := &deliverer.Request{
+		Request: c.Request,
+		Body:    c.GetRequestBody(),
+		RawBody: compressor.RawBody(),
+		Timeout: time.Duration(endpoint.Request.Timeout) * time.Millisecond,
+		TLS:     tlsOptions,
+		Config:  endpoint.Config,
+	}
+
+	startAt := time.Now()
+	response := w.send(ctx, iterator, endpoint, c, request)
+	finishAt := time.Now()
+
+	if response.Error != nil {
+		w.log.Infof("failed to delivery batch: %v", response.Error)
+	}
+	w.log.Debugf("batch delivery response: %v", response)
+
+	attempt := batch.Tasks[0].Data.(*taskqueue.MessageData).Attempt
+	result := newAttemptResult(endpoint, request, response, attempt, startAt, finishAt)
+	w.report(ctx, endpoint, permit, result, response)
+
+	for _, task := range batch.Tasks {
+		r := *result
+		r.ID = task.ID
+		if _, err := w.complete(ctx, task, endpoint, &r, response, finishAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
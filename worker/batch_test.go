@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/taskqueue"
+)
+
+func newBatchTask(id string, attempt int, event string) *taskqueue.TaskMessage {
+	return &taskqueue.TaskMessage{
+		ID:   id,
+		Data: &taskqueue.MessageData{EndpointId: "e1", Attempt: attempt, Event: event},
+	}
+}
+
+func TestBatcher(t *testing.T) {
+	endpoint := &entities.Endpoint{
+		ID: "e1",
+		Delivery: &entities.Delivery{
+			Mode:      entities.DeliveryModeBatch,
+			MaxEvents: 3,
+			MaxBytes:  1024,
+			MaxWait:   100,
+		},
+	}
+
+	var mux sync.Mutex
+	var flushed [][]string
+	b := newBatcher(func(batch *Batch) {
+		mux.Lock()
+		defer mux.Unlock()
+		ids := make([]string, 0, len(batch.Tasks))
+		for _, task := range batch.Tasks {
+			ids = append(ids, task.ID)
+		}
+		flushed = append(flushed, ids)
+	})
+	batches := func() [][]string {
+		mux.Lock()
+		defer mux.Unlock()
+		return flushed
+	}
+
+	t.Run("should flush when max_events is reached", func(t *testing.T) {
+		flushed = nil
+		for _, id := range []string{"1", "2", "3"} {
+			b.Add(endpoint, newBatchTask(id, 1, "{}"))
+		}
+		assert.Eventually(t, func() bool { return len(batches()) == 1 }, time.Second, time.Millisecond*10)
+		assert.Equal(t, [][]string{{"1", "2", "3"}}, batches())
+	})
+
+	t.Run("should flush when max_wait elapsed", func(t *testing.T) {
+		flushed = nil
+		b.Add(endpoint, newBatchTask("1", 1, "{}"))
+		assert.Empty(t, batches())
+		assert.Eventually(t, func() bool { return len(batches()) == 1 }, time.Second, time.Millisecond*10)
+		assert.Equal(t, [][]string{{"1"}}, batches())
+	})
+
+	t.Run("should flush before max_bytes is exceeded", func(t *testing.T) {
+		flushed = nil
+		b.Add(endpoint, newBatchTask("1", 1, strings.Repeat("a", 600)))
+		b.Add(endpoint, newBatchTask("2", 1, strings.Repeat("a", 600)))
+		b.Add(endpoint, newBatchTask("3", 1, strings.Repeat("a", 2000)))
+		assert.Eventually(t, func() bool { return len(batches()) == 3 }, time.Second, time.Millisecond*10)
+		assert.ElementsMatch(t, [][]string{{"1"}, {"2"}, {"3"}}, batches())
+	})
+
+	t.Run("should batch by attempt number", func(t *testing.T) {
+		flushed = nil
+		b.Add(endpoint, newBatchTask("1", 1, "{}"))
+		b.Add(endpoint, newBatchTask("2", 2, "{}"))
+		b.Add(endpoint, newBatchTask("3", 1, "{}"))
+		b.Close()
+		assert.ElementsMatch(t, [][]string{{"1", "3"}, {"2"}}, batches())
+	})
+}
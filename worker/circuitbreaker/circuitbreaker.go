@@ -1,5 +1,7 @@
 package circuitbreaker
 
+import "time"
+
 type State string
 
 const (
@@ -37,12 +39,28 @@ type CircuitBreaker interface {
 
 	// Metric returns CircuitBreaker metric
 	Metric() TimeBucketMetric
+
+	// Buckets returns the metrics of time buckets that the time window covers
+	Buckets() []TimeBucketMetric
+
+	// OpenedAt returns the time when CircuitBreaker was opened last time
+	OpenedAt() time.Time
+
+	// Reason returns the reason why CircuitBreaker was opened last time
+	Reason() string
+
+	// ResetAt returns the time when CircuitBreaker was closed last time
+	ResetAt() time.Time
 }
 
 type circuitBreaker struct {
-	name   string
-	state  State
-	metric TimeBucketMetric
+	name     string
+	state    State
+	metric   TimeBucketMetric
+	buckets  []TimeBucketMetric
+	openedAt time.Time
+	reason   string
+	resetAt  time.Time
 }
 
 func (c *circuitBreaker) Name() string {
@@ -56,3 +74,19 @@ func (c *circuitBreaker) State() State {
 func (c *circuitBreaker) Metric() TimeBucketMetric {
 	return c.metric
 }
+
+func (c *circuitBreaker) Buckets() []TimeBucketMetric {
+	return c.buckets
+}
+
+func (c *circuitBreaker) OpenedAt() time.Time {
+	return c.openedAt
+}
+
+func (c *circuitBreaker) Reason() string {
+	return c.reason
+}
+
+func (c *circuitBreaker) ResetAt() time.Time {
+	return c.resetAt
+}
@@ -9,13 +9,18 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"github.com/webhookx-io/webhookx/utils"
 	"github.com/webhookx-io/webhookx/worker/circuitbreaker/metrics"
 	"go.uber.org/zap"
 )
 
 const (
 	defaultFlushInterval = time.Second * 10
+	defaultCooldown      = time.Second * 60
+	defaultProbes        = 3
+	defaultHalfOpenWait  = time.Second * 5
 
 	timeFormatHour   = "2006-01-02T15Z"
 	timeFormatMinute = "2006-01-02T15:04Z"
@@ -26,6 +31,36 @@ var (
 	cacheKey             = constants.CacheKey{Name: "cb", Version: "v1"}
 )
 
+// Thresholds overrides the thresholds of Manager for a circuit breaker, zero values fall back to the Manager's.
+type Thresholds struct {
+	WindowSize              time.Duration
+	FailureRateThreshold    int
+	MinimumRequestThreshold int
+}
+
+// EndpointThresholds returns the thresholds that the endpoint overrides,
+// returns false if the endpoint opted out of circuit breaker.
+func EndpointThresholds(endpoint *entities.Endpoint) (*Thresholds, bool) {
+	cb := endpoint.CircuitBreaker
+	if cb == nil {
+		return nil, true
+	}
+	if !cb.Enabled {
+		return nil, false
+	}
+	t := &Thresholds{}
+	if cb.WindowSize != nil {
+		t.WindowSize = time.Duration(*cb.WindowSize) * time.Second
+	}
+	if cb.FailureRateThreshold != nil {
+		t.FailureRateThreshold = *cb.FailureRateThreshold
+	}
+	if cb.MinimumRequestThreshold != nil {
+		t.MinimumRequestThreshold = *cb.MinimumRequestThreshold
+	}
+	return t, true
+}
+
 type Option func(m *Manager)
 
 func WithTimeWindowSize(seconds int) Option {
@@ -52,6 +87,18 @@ func WithNowFunc(now func() time.Time) Option {
 	return func(m *Manager) { m.now = now }
 }
 
+func WithCooldown(seconds int) Option {
+	return func(m *Manager) { m.cooldown = time.Duration(seconds) * time.Second }
+}
+
+func WithHalfOpenProbes(probes int) Option {
+	return func(m *Manager) { m.halfOpenProbes = probes }
+}
+
+func WithHalfOpenWait(wait time.Duration) Option {
+	return func(m *Manager) { m.halfOpenWait = wait }
+}
+
 func WithEnabled(enabled bool) Option {
 	return func(m *Manager) { m.enabled = enabled }
 }
@@ -68,6 +115,9 @@ type Manager struct {
 	failureRateThreshold    int
 	minimumRequestThreshold int
 	flushInterval           time.Duration
+	cooldown                time.Duration
+	halfOpenProbes          int
+	halfOpenWait            time.Duration
 	now                     func() time.Time
 
 	flushMux sync.Mutex
@@ -86,9 +136,12 @@ func NewManager(opts ...Option) *Manager {
 		log:                     zap.S().Named("circuitbreaker"),
 		cache:                   make(map[string]*Recorder),
 		flushInterval:           DefaultFlushInterval,
-		timeWindow:              3600,
+		timeWindow:              time.Hour,
 		failureRateThreshold:    80,
 		minimumRequestThreshold: 100,
+		cooldown:                defaultCooldown,
+		halfOpenProbes:          defaultProbes,
+		halfOpenWait:            defaultHalfOpenWait,
 		enabled:                 true,
 		now:                     time.Now,
 	}
@@ -100,6 +153,10 @@ func NewManager(opts ...Option) *Manager {
 	return manager
 }
 
+func (m *Manager) Enabled() bool {
+	return m.enabled
+}
+
 func (m *Manager) Record(time time.Time, id string, event metrics.Event) {
 	if !m.enabled {
 		return
@@ -220,20 +277,31 @@ func (m *Manager) Flush(ctx context.Context) error {
 	return nil
 }
 
-func (m *Manager) GetCircuitBreaker(ctx context.Context, id string) (CircuitBreaker, error) {
+// GetCircuitBreaker returns the circuit breaker with its metric in time window,
+// the requests before the circuit breaker was closed last time are excluded from the metric.
+func (m *Manager) GetCircuitBreaker(ctx context.Context, id string, thresholds *Thresholds) (CircuitBreaker, error) {
+	state, err := m.loadState(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	cb := &circuitBreaker{
-		name:  id,
-		state: StateClosed,
+		name:     id,
+		state:    state.state,
+		openedAt: state.openedAt,
+		reason:   state.reason,
+		resetAt:  state.resetAt,
 	}
 
 	now := m.now()
+	timeWindow := m.thresholds(thresholds).WindowSize
 
 	pipeline := m.client.Pipeline()
 	cmds := make([]*redis.MapStringStringCmd, 0)
 	metrics := make([]TimeBucketMetric, 0)
 
-	if m.timeWindow < time.Hour {
-		start := now.Add(-m.timeWindow).Truncate(time.Minute)
+	if timeWindow < time.Hour {
+		start := now.Add(-timeWindow).Truncate(time.Minute)
 		end := now.Truncate(time.Minute)
 		for t := start; !t.After(end); t = t.Add(time.Minute) {
 			minuteKey := cacheKey.Build(id, ":", t.UTC().Format(timeFormatMinute))
@@ -244,7 +312,7 @@ func (m *Manager) GetCircuitBreaker(ctx context.Context, id string) (CircuitBrea
 			})
 		}
 	} else {
-		start := now.Add(-m.timeWindow).Truncate(time.Hour)
+		start := now.Add(-timeWindow).Truncate(time.Hour)
 		end := now.Truncate(time.Hour)
 		for t := start; !t.After(end); t = t.Add(time.Hour) {
 			hourKey := cacheKey.Build(id, ":", t.UTC().Format(timeFormatHour))
@@ -256,7 +324,7 @@ func (m *Manager) GetCircuitBreaker(ctx context.Context, id string) (CircuitBrea
 		}
 	}
 
-	_, err := pipeline.Exec(ctx)
+	_, err = pipeline.Exec(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -275,17 +343,63 @@ func (m *Manager) GetCircuitBreaker(ctx context.Context, id string) (CircuitBrea
 		m.Error += int64(failure)
 	}
 
-	metric := timeProrate(metrics, now.Unix(), int64(m.timeWindow.Seconds()))
-	cb.metric = metric
-
-	failureRate := float64(m.failureRateThreshold) / 100.0
-	if metric.TotalRequest() >= int64(m.minimumRequestThreshold) &&
-		metric.FailureRate() >= failureRate {
-		cb.state = StateOpen
+	windowSize := int64(timeWindow.Seconds())
+	if !state.resetAt.IsZero() {
+		windowSize = min(windowSize, now.Unix()-state.resetAt.Unix())
 	}
+	cb.buckets = metrics
+	cb.metric = timeProrate(metrics, now.Unix(), windowSize)
 	return cb, nil
 }
 
+// Evaluate opens the CLOSED circuit breaker when the failure rate in time window exceeds the threshold,
+// returns true if the circuit breaker has been opened.
+func (m *Manager) Evaluate(ctx context.Context, id string, thresholds *Thresholds) (CircuitBreaker, bool, error) {
+	t := m.thresholds(thresholds)
+	cb, err := m.GetCircuitBreaker(ctx, id, &t)
+	if err != nil {
+		return nil, false, err
+	}
+	if cb.State() != StateClosed {
+		return cb, false, nil
+	}
+
+	metric := cb.Metric()
+	failureRate := float64(t.FailureRateThreshold) / 100.0
+	if metric.TotalRequest() < int64(t.MinimumRequestThreshold) || metric.FailureRate() < failureRate {
+		return cb, false, nil
+	}
+
+	reason := fmt.Sprintf("failure rate %.2f%% exceeded the threshold %d%% (%d requests)",
+		metric.FailureRate()*100, t.FailureRateThreshold, metric.TotalRequest())
+	opened, err := m.Trip(ctx, id, reason)
+	if err != nil {
+		return nil, false, err
+	}
+	if opened {
+		c := cb.(*circuitBreaker)
+		c.state = StateOpen
+		c.openedAt = m.now()
+		c.reason = reason
+	}
+	return cb, opened, nil
+}
+
+// thresholds returns the thresholds that the overrides applied to
+func (m *Manager) thresholds(overrides *Thresholds) Thresholds {
+	t := Thresholds{
+		WindowSize:              m.timeWindow,
+		FailureRateThreshold:    m.failureRateThreshold,
+		MinimumRequestThreshold: m.minimumRequestThreshold,
+	}
+	if overrides != nil {
+		t.WindowSize = utils.DefaultIfZero(overrides.WindowSize, t.WindowSize)
+		t.FailureRateThreshold = utils.DefaultIfZero(overrides.FailureRateThreshold, t.FailureRateThreshold)
+		t.MinimumRequestThreshold = utils.DefaultIfZero(overrides.MinimumRequestThreshold, t.MinimumRequestThreshold)
+	}
+	return t
+}
+
 func timeProrate(metrics []TimeBucketMetric, now int64, windowSize int64) TimeBucketMetric {
 	windowStart := now - windowSize
 	var success float64
@@ -0,0 +1,187 @@
+package circuitbreaker
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// tripScript opens a closed circuit breaker
+	tripScript = redis.NewScript(`
+		local state = redis.call('HGET', KEYS[1], 'state')
+		if state and state ~= 'CLOSED' then
+			return 0
+		end
+		redis.call('HSET', KEYS[1], 'state', 'OPEN', 'opened_at', ARGV[1], 'reason', ARGV[2])
+		return 1
+	`)
+
+	// acquireScript transitions an OPEN circuit breaker to HALF_OPEN once cool-down elapsed,
+	// and hands out a limited number of probes in HALF_OPEN.
+	acquireScript = redis.NewScript(`
+		local now = tonumber(ARGV[1])
+		local cooldown = tonumber(ARGV[2])
+		local probes = tonumber(ARGV[3])
+		local state = redis.call('HGET', KEYS[1], 'state')
+		if not state or state == 'CLOSED' then
+			return { 'CLOSED', 1, 0 }
+		end
+		if state == 'OPEN' then
+			local opened_at = tonumber(redis.call('HGET', KEYS[1], 'opened_at'))
+			if now - opened_at < cooldown then
+				return { 'OPEN', 0, opened_at + cooldown }
+			end
+			state = 'HALF_OPEN'
+			redis.call('HSET', KEYS[1], 'state', state, 'half_opened_at', now, 'probes', 0, 'successes', 0)
+		end
+		-- re-issues probes in case the outcome of probes is never reported
+		local half_opened_at = tonumber(redis.call('HGET', KEYS[1], 'half_opened_at'))
+		if now - half_opened_at >= cooldown then
+			redis.call('HSET', KEYS[1], 'half_opened_at', now, 'probes', 0, 'successes', 0)
+		end
+		local n = redis.call('HINCRBY', KEYS[1], 'probes', 1)
+		if n <= probes then
+			return { 'HALF_OPEN', 1, 0 }
+		end
+		return { 'HALF_OPEN', 0, 0 }
+	`)
+
+	// reportScript closes a HALF_OPEN circuit breaker when all probes succeeded, re-opens it when any probe failed.
+	reportScript = redis.NewScript(`
+		local state = redis.call('HGET', KEYS[1], 'state')
+		if state ~= 'HALF_OPEN' then
+			return state or 'CLOSED'
+		end
+		if ARGV[2] == '1' then
+			local n = redis.call('HINCRBY', KEYS[1], 'successes', 1)
+			if n < tonumber(ARGV[3]) then
+				return 'HALF_OPEN'
+			end
+			redis.call('DEL', KEYS[1])
+			redis.call('HSET', KEYS[1], 'state', 'CLOSED', 'reset_at', ARGV[1])
+			return 'CLOSED'
+		end
+		redis.call('HSET', KEYS[1], 'state', 'OPEN', 'opened_at', ARGV[1], 'reason', 'probe failed')
+		return 'OPEN'
+	`)
+)
+
+// Permit is the result of acquiring a circuit breaker
+type Permit struct {
+	State   State
+	Allowed bool
+	// RetryAt is the time when the request should be retried if not allowed
+	RetryAt time.Time
+}
+
+// IsProbe reports whether the request is a probe of a HALF_OPEN circuit breaker
+func (p Permit) IsProbe() bool {
+	return p.Allowed && p.State == StateHalfOpen
+}
+
+type persistentState struct {
+	state    State
+	openedAt time.Time
+	reason   string
+	resetAt  time.Time
+}
+
+func (m *Manager) stateKey(id string) string {
+	return cacheKey.Build(id, ":state")
+}
+
+func (m *Manager) loadState(ctx context.Context, id string) (*persistentState, error) {
+	v, err := m.client.HGetAll(ctx, m.stateKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	s := &persistentState{state: StateClosed}
+	if state, ok := v["state"]; ok {
+		s.state = State(state)
+	}
+	if ms, err := strconv.ParseInt(v["opened_at"], 10, 64); err == nil {
+		s.openedAt = time.UnixMilli(ms)
+	}
+	s.reason = v["reason"]
+	if ms, err := strconv.ParseInt(v["reset_at"], 10, 64); err == nil {
+		s.resetAt = time.UnixMilli(ms)
+	}
+	return s, nil
+}
+
+// Trip opens the circuit breaker if it is CLOSED, returns true if the state changed.
+func (m *Manager) Trip(ctx context.Context, id string, reason string) (bool, error) {
+	n, err := tripScript.Run(ctx, m.client, []string{m.stateKey(id)}, m.now().UnixMilli(), reason).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Acquire checks whether a request is allowed to pass through the circuit breaker.
+// An OPEN circuit breaker rejects requests until the cool-down elapsed, then becomes HALF_OPEN
+// that allows a limited number of probe requests.
+func (m *Manager) Acquire(ctx context.Context, id string) (Permit, error) {
+	if !m.enabled {
+		return Permit{State: StateClosed, Allowed: true}, nil
+	}
+
+	now := m.now()
+	res, err := acquireScript.Run(ctx, m.client, []string{m.stateKey(id)},
+		now.UnixMilli(), m.cooldown.Milliseconds(), m.halfOpenProbes).Slice()
+	if err != nil {
+		return Permit{}, err
+	}
+
+	permit := Permit{
+		State:   State(res[0].(string)),
+		Allowed: res[1].(int64) == 1,
+	}
+	if !permit.Allowed {
+		switch permit.State {
+		case StateOpen:
+			permit.RetryAt = time.UnixMilli(res[2].(int64))
+		case StateHalfOpen:
+			permit.RetryAt = now.Add(m.halfOpenWait)
+		}
+	}
+	return permit, nil
+}
+
+// ReportProbe reports the outcome of a probe request, returns the state of circuit breaker after reported.
+func (m *Manager) ReportProbe(ctx context.Context, id string, success bool) (State, error) {
+	v := "0"
+	if success {
+		v = "1"
+	}
+	state, err := reportScript.Run(ctx, m.client, []string{m.stateKey(id)},
+		m.now().UnixMilli(), v, m.halfOpenProbes).Text()
+	if err != nil {
+		return "", err
+	}
+	return State(state), nil
+}
+
+// Reset closes the circuit breaker and clears its recorded metrics in redis.
+// The requests recorded before reset but not yet flushed are excluded from the metric as well.
+func (m *Manager) Reset(ctx context.Context, id string) error {
+	now := m.now()
+	keys := []string{m.stateKey(id)}
+	for i := 0; i <= 60; i++ {
+		t := now.Add(-time.Duration(i) * time.Minute)
+		keys = append(keys, cacheKey.Build(id, ":", t.UTC().Format(timeFormatMinute)))
+	}
+	for i := 0; i <= 24; i++ {
+		t := now.Add(-time.Duration(i) * time.Hour)
+		keys = append(keys, cacheKey.Build(id, ":", t.UTC().Format(timeFormatHour)))
+	}
+
+	pipeline := m.client.TxPipeline()
+	pipeline.Del(ctx, keys...)
+	pipeline.HSet(ctx, m.stateKey(id), "state", string(StateClosed), "reset_at", now.UnixMilli())
+	_, err := pipeline.Exec(ctx)
+	return err
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/compression"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
+// compressor compresses the request body in the stage of endpoint's compression,
+// the methods of nil compressor (the endpoint has no compression) do nothing.
+type compressor struct {
+	compression *entities.Compression
+	done        bool
+	raw         []byte
+}
+
+func newCompressor(endpoint *entities.Endpoint) *compressor {
+	if endpoint.Request.Compression == nil {
+		return nil
+	}
+	return &compressor{compression: endpoint.Request.Compression}
+}
+
+// BeforePlugin is called before executing an outbound plugin, it compresses the body before the first signing plugin
+func (c *compressor) BeforePlugin(ctx *plugin.Context, p plugin.Plugin) error {
+	if c == nil || c.done || c.compression.Stage != entities.CompressionStageBeforeSigning {
+		return nil
+	}
+	if signer, ok := p.(plugin.Signer); ok && signer.SignsBody() {
+		return c.compress(ctx)
+	}
+	return nil
+}
+
+// AfterPlugins is called after executing all outbound plugins, it compresses the body if it's not compressed yet
+func (c *compressor) AfterPlugins(ctx *plugin.Context) error {
+	if c == nil || c.done {
+		return nil
+	}
+	return c.compress(ctx)
+}
+
+// RawBody returns the body before compression, or nil if the body is not compressed
+func (c *compressor) RawBody() []byte {
+	if c == nil {
+		return nil
+	}
+	return c.raw
+}
+
+func (c *compressor) compress(ctx *plugin.Context) error {
+	c.done = true
+	body := ctx.GetRequestBody()
+	if len(body) < c.compression.MinSize || ctx.Request.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	compressed, err := compression.Compress(c.compression.Algorithm, body)
+	if err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	c.raw = body
+	ctx.SetRequestBody(compressed)
+	ctx.Request.Header.Set("Content-Encoding", c.compression.Algorithm)
+	return nil
+}
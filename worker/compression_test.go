@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/compression"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/tracing/instrumentations"
+	"github.com/webhookx-io/webhookx/plugins/transform"
+	"github.com/webhookx-io/webhookx/plugins/webhookx_signature"
+)
+
+func TestCompressor(t *testing.T) {
+	body := bytes.Repeat([]byte(`{"foo":"bar"}`), 100)
+	newContext := func(body []byte) *plugin.Context {
+		r, _ := http.NewRequest("POST", "https://example.com", nil)
+		c := plugin.NewContext(context.TODO(), r, nil)
+		c.SetRequestBody(body)
+		return c
+	}
+	newEndpoint := func(compression *entities.Compression) *entities.Endpoint {
+		return &entities.Endpoint{Request: entities.RequestConfig{Compression: compression}}
+	}
+
+	t.Run("should do nothing without compression", func(t *testing.T) {
+		compressor := newCompressor(newEndpoint(nil))
+		c := newContext(body)
+		assert.NoError(t, compressor.BeforePlugin(c, &webhookx_signature.SignaturePlugin{}))
+		assert.NoError(t, compressor.AfterPlugins(c))
+		assert.Equal(t, body, c.GetRequestBody())
+		assert.Nil(t, compressor.RawBody())
+	})
+
+	t.Run("should compress after plugins", func(t *testing.T) {
+		for _, algorithm := range []string{compression.Gzip, compression.Zstd, compression.Deflate} {
+			compressor := newCompressor(newEndpoint(&entities.Compression{
+				Algorithm: algorithm,
+				Stage:     entities.CompressionStageAfterPlugins,
+			}))
+			c := newContext(body)
+			assert.NoError(t, compressor.BeforePlugin(c, &webhookx_signature.SignaturePlugin{}))
+			assert.Equal(t, body, c.GetRequestBody())
+			assert.NoError(t, compressor.AfterPlugins(c))
+			assert.Equal(t, algorithm, c.Request.Header.Get("Content-Encoding"))
+			assert.Equal(t, body, compressor.RawBody())
+			decompressed, err := compression.Decompress(algorithm, c.GetRequestBody())
+			assert.NoError(t, err)
+			assert.Equal(t, body, decompressed)
+		}
+	})
+
+	t.Run("should compress before signing plugin", func(t *testing.T) {
+		compressor := newCompressor(newEndpoint(&entities.Compression{
+			Algorithm: compression.Gzip,
+			Stage:     entities.CompressionStageBeforeSigning,
+		}))
+		c := newContext(body)
+		assert.NoError(t, compressor.BeforePlugin(c, &transform.TransformPlugin{}))
+		assert.Equal(t, body, c.GetRequestBody())
+		assert.NoError(t, compressor.BeforePlugin(c, &webhookx_signature.SignaturePlugin{}))
+		compressed := c.GetRequestBody()
+		assert.NotEqual(t, body, compressed)
+		assert.NoError(t, compressor.AfterPlugins(c))
+		assert.Equal(t, compressed, c.GetRequestBody())
+		assert.Equal(t, body, compressor.RawBody())
+	})
+
+	t.Run("should compress before instrumented signing plugin", func(t *testing.T) {
+		compressor := newCompressor(newEndpoint(&entities.Compression{
+			Algorithm: compression.Gzip,
+			Stage:     entities.CompressionStageBeforeSigning,
+		}))
+		c := newContext(body)
+		assert.NoError(t, compressor.BeforePlugin(c, instrumentations.NewInstrumentedPlugin(&transform.TransformPlugin{})))
+		assert.Equal(t, body, c.GetRequestBody())
+		assert.NoError(t, compressor.BeforePlugin(c, instrumentations.NewInstrumentedPlugin(&webhookx_signature.SignaturePlugin{})))
+		assert.NotEqual(t, body, c.GetRequestBody())
+		assert.Equal(t, body, compressor.RawBody())
+	})
+
+	t.Run("should skip compression below min size", func(t *testing.T) {
+		compressor := newCompressor(newEndpoint(&entities.Compression{
+			Algorithm: compression.Gzip,
+			MinSize:   len(body) + 1,
+		}))
+		c := newContext(body)
+		assert.NoError(t, compressor.AfterPlugins(c))
+		assert.Equal(t, body, c.GetRequestBody())
+		assert.Empty(t, c.Request.Header.Get("Content-Encoding"))
+		assert.Nil(t, compressor.RawBody())
+	})
+}
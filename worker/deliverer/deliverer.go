@@ -3,8 +3,12 @@ package deliverer
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/utils"
 )
 
 type Deliverer interface {
@@ -14,7 +18,21 @@ type Deliverer interface {
 type Request struct {
 	Request *http.Request
 	Body    []byte
+	// RawBody is the body before compression, it is nil if the body is not compressed
+	RawBody []byte
 	Timeout time.Duration
+	TLS     *TLSOptions
+	// Config is the config of non-HTTP endpoint, e.g. the topic of kafka endpoint
+	Config entities.EndpointConfig
+}
+
+// decodeConfig decodes the config of endpoint type into v
+func decodeConfig(request *Request, typ entities.EndpointType, v interface{}) error {
+	config := request.Config[string(typ)]
+	if config == nil {
+		return fmt.Errorf("%s config is missing", typ)
+	}
+	return utils.MapToStruct(config, v)
 }
 
 type AclDecision struct {
@@ -22,20 +40,54 @@ type AclDecision struct {
 }
 
 type Response struct {
-	Request         *Request
-	ACL             AclDecision
-	StatusCode      int
-	Header          http.Header
-	ResponseBody    []byte
-	Error           error
+	Request      *Request
+	ACL          AclDecision
+	StatusCode   int
+	Header       http.Header
+	ResponseBody []byte
+	// ResponseBodyTruncated reports whether the response body exceeds the maximum size and is truncated
+	ResponseBodyTruncated bool
+	Error                 error
+	// ErrorCode is the error code of Error that is classified by the deliverer, e.g. the broker is unavailable
+	ErrorCode       entities.AttemptErrorCode
 	Latancy         time.Duration
 	ProxyStatusCode int
 }
 
+// readBody reads the body up to max bytes (no limit if max is zero), it reports whether the body is truncated
+func readBody(body io.Reader, max int64) ([]byte, bool, error) {
+	if max <= 0 {
+		b, err := io.ReadAll(body)
+		return b, false, err
+	}
+	b, err := io.ReadAll(io.LimitReader(body, max+1))
+	if int64(len(b)) > max {
+		return b[:max], true, err
+	}
+	return b, false, err
+}
+
+// truncateBody truncates the body that is not read from a stream to max bytes
+func truncateBody(body []byte, max int64) ([]byte, bool) {
+	if max > 0 && int64(len(body)) > max {
+		return body[:max], true
+	}
+	return body, false
+}
+
 func (r *Response) Is2xx() bool {
 	return r.StatusCode >= 200 && r.StatusCode <= 299
 }
 
+// Succeeded reports whether the request is delivered. The message deliverers have no status code,
+// the messages are delivered once the brokers acknowledge them.
+func (r *Response) Succeeded() bool {
+	if r.Error != nil {
+		return false
+	}
+	return r.StatusCode == 0 || r.Is2xx()
+}
+
 func (r *Response) String() string {
 	return fmt.Sprintf("%s %s %d %dms",
 		r.Request.Request.Method,
@@ -0,0 +1,272 @@
+package deliverer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/grpcdesc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const EndpointTypeGRPC entities.EndpointType = "grpc"
+
+type GRPCEndpointConfig struct {
+	// Method is the full name of unary method, e.g. acme.webhook.v1.WebhookService/HandleEvent
+	Method string `json:"method"`
+	// DescriptorSet is the base64 encoded FileDescriptorSet of method, the server reflection is used if nil
+	DescriptorSet *string `json:"descriptor_set"`
+}
+
+// grpcStatusCodes maps the gRPC status codes onto HTTP status codes,
+// so that the responses are handled by retry policy as HTTP responses.
+var grpcStatusCodes = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// GRPCStatusCode returns the HTTP status code of gRPC status code
+func GRPCStatusCode(code codes.Code) int {
+	if statusCode, ok := grpcStatusCodes[code]; ok {
+		return statusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// validateGRPCConfig validates the method and the descriptor set, it returns the error fields
+func validateGRPCConfig(config *GRPCEndpointConfig) map[string]interface{} {
+	if _, _, err := grpcdesc.SplitMethod(config.Method); err != nil {
+		return map[string]interface{}{"method": err.Error()}
+	}
+	if config.DescriptorSet != nil {
+		files, err := grpcdesc.ParseDescriptorSet(*config.DescriptorSet)
+		if err == nil {
+			_, err = grpcdesc.FindMethod(files, config.Method)
+		}
+		if err != nil {
+			return map[string]interface{}{"descriptor_set": err.Error()}
+		}
+	}
+	return nil
+}
+
+// reservedMetadata is the request headers that are not sent as gRPC metadata
+var reservedMetadata = map[string]bool{
+	"content-type":      true,
+	"content-length":    true,
+	"connection":        true,
+	"host":              true,
+	"te":                true,
+	"transfer-encoding": true,
+	"user-agent":        true,
+}
+
+// GRPCDeliverer delivers via gRPC by calling a unary method, the request url is the server address
+// (e.g. grpc://localhost:50051, or grpcs://localhost:50051 for TLS).
+// The request body is mapped into the request message as protobuf JSON, and the request headers are sent as metadata.
+// The gRPC status code is mapped onto HTTP status code (e.g. UNAVAILABLE is 503), and the gRPC status
+// is recorded in the Grpc-Status and Grpc-Message response headers.
+type GRPCDeliverer struct {
+	messageDeliverer
+
+	mux     sync.Mutex
+	conns   *expirable.LRU[string, *grpc.ClientConn]
+	methods *expirable.LRU[string, protoreflect.MethodDescriptor]
+}
+
+func NewGRPCDeliverer(opts Options) *GRPCDeliverer {
+	return &GRPCDeliverer{
+		messageDeliverer: newMessageDeliverer(opts),
+		conns: expirable.NewLRU[string, *grpc.ClientConn](maxBrokerClients, func(_ string, conn *grpc.ClientConn) {
+			_ = conn.Close()
+		}, brokerClientTTL),
+		methods: expirable.NewLRU[string, protoreflect.MethodDescriptor](maxBrokerClients, nil, brokerClientTTL),
+	}
+}
+
+func (d *GRPCDeliverer) getConn(request *Request) (*grpc.ClientConn, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	key := clientKey(request)
+	if conn, ok := d.conns.Get(key); ok {
+		return conn, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if request.Request.URL.Scheme == "grpcs" {
+		config, err := tlsConfig(request)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(config)
+	}
+	conn, err := grpc.NewClient("passthrough:///"+request.Request.URL.Host,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return d.dial(ctx, "tcp", addr)
+		}),
+		grpc.WithUserAgent("WebhookX"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.conns.Add(key, conn)
+	return conn, nil
+}
+
+// getMethod returns the descriptor of method, it is resolved from the descriptor set or via the server reflection
+func (d *GRPCDeliverer) getMethod(ctx context.Context, conn *grpc.ClientConn, request *Request, config *GRPCEndpointConfig) (protoreflect.MethodDescriptor, error) {
+	key := clientKey(request, config.Method)
+	if config.DescriptorSet != nil {
+		sum := sha256.Sum256([]byte(*config.DescriptorSet))
+		key = config.Method + "|" + hex.EncodeToString(sum[:])
+	}
+	if method, ok := d.methods.Get(key); ok {
+		return method, nil
+	}
+
+	var method protoreflect.MethodDescriptor
+	var err error
+	if config.DescriptorSet != nil {
+		files, err := grpcdesc.ParseDescriptorSet(*config.DescriptorSet)
+		if err != nil {
+			return nil, err
+		}
+		method, err = grpcdesc.FindMethod(files, config.Method)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		method, err = grpcdesc.Reflect(ctx, conn, config.Method)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve method via server reflection: %w", err)
+		}
+	}
+	d.methods.Add(key, method)
+	return method, nil
+}
+
+// grpcMetadata returns the request headers as gRPC metadata
+func grpcMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for name, values := range header {
+		name = strings.ToLower(name)
+		if reservedMetadata[name] || strings.HasPrefix(name, "grpc-") {
+			continue
+		}
+		md.Append(name, values...)
+	}
+	return md
+}
+
+func (d *GRPCDeliverer) Send(ctx context.Context, request *Request) *Response {
+	ctx, res, done := d.start(ctx, "grpc.invoke", request,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("rpc.system", "grpc")),
+	)
+	defer done()
+
+	var config GRPCEndpointConfig
+	if res.Error = decodeConfig(request, EndpointTypeGRPC, &config); res.Error != nil {
+		return res
+	}
+
+	if res.Error = d.checkACL(ctx, res, request.Request.URL.Host); res.Error != nil {
+		return res
+	}
+
+	conn, err := d.getConn(request)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+
+	method, err := d.getMethod(ctx, conn, request, &config)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("rpc.service", string(method.Parent().FullName())),
+		attribute.String("rpc.method", string(method.Name())),
+	)
+
+	in := dynamicpb.NewMessage(method.Input())
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(request.Body, in); err != nil {
+		res.Error = fmt.Errorf("failed to map request body into %s: %w", method.Input().FullName(), err)
+		return res
+	}
+	out := dynamicpb.NewMessage(method.Output())
+
+	var header, trailer metadata.MD
+	ctx = metadata.NewOutgoingContext(ctx, grpcMetadata(request.Request.Header))
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	err = conn.Invoke(ctx, fullMethod, in, out, grpc.Header(&header), grpc.Trailer(&trailer))
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		res.Error = errors.Join(context.DeadlineExceeded, err)
+		return res
+	}
+
+	s := status.Convert(err)
+	res.StatusCode = GRPCStatusCode(s.Code())
+	res.Header = http.Header{}
+	for _, md := range []metadata.MD{header, trailer} {
+		for name, values := range md {
+			if strings.HasSuffix(name, "-bin") {
+				continue
+			}
+			for _, value := range values {
+				res.Header.Add(name, value)
+			}
+		}
+	}
+	res.Header.Set("Grpc-Status", fmt.Sprint(int(s.Code())))
+	if s.Code() == codes.OK {
+		body, _ := protojson.Marshal(out)
+		res.ResponseBody, res.ResponseBodyTruncated = truncateBody(body, d.opts.MaxResponseBodySize)
+	} else {
+		res.Header.Set("Grpc-Message", s.Message())
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("rpc.grpc.status_code", int(s.Code())))
+	return res
+}
+
+func (d *GRPCDeliverer) Close() error {
+	d.conns.Purge()
+	return nil
+}
@@ -0,0 +1,133 @@
+package deliverer
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func startHealthServer(t *testing.T) (string, chan metadata.MD) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	received := make(chan metadata.MD, 1)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		select {
+		case received <- md:
+		default:
+		}
+		return handler(ctx, req)
+	}))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("webhookx", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String(), received
+}
+
+func newGRPCRequest(t *testing.T, addr string, body string, config *GRPCEndpointConfig) *Request {
+	request := newMessageRequest(t, "grpc://"+addr, entities.EndpointConfig{"grpc": utils.Must(utils.StructToMap(config))})
+	request.Body = []byte(body)
+	return request
+}
+
+func TestGRPCStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusOK, GRPCStatusCode(codes.OK))
+	assert.Equal(t, http.StatusServiceUnavailable, GRPCStatusCode(codes.Unavailable))
+	assert.Equal(t, http.StatusTooManyRequests, GRPCStatusCode(codes.ResourceExhausted))
+	assert.Equal(t, http.StatusNotFound, GRPCStatusCode(codes.NotFound))
+	assert.Equal(t, http.StatusInternalServerError, GRPCStatusCode(codes.Code(100)))
+}
+
+func TestGRPCDeliverer(t *testing.T) {
+	addr, received := startHealthServer(t)
+
+	fd := protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto)
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fd}})
+	assert.NoError(t, err)
+	descriptorSet := base64.StdEncoding.EncodeToString(b)
+
+	t.Run("should call method resolved via server reflection", func(t *testing.T) {
+		d := NewGRPCDeliverer(Options{RequestTimeout: time.Second})
+		defer func() { _ = d.Close() }()
+		res := d.Send(context.TODO(), newGRPCRequest(t, addr, `{"service":"webhookx","unknown":1}`,
+			&GRPCEndpointConfig{Method: "grpc.health.v1.Health/Check"}))
+		assert.NoError(t, res.Error)
+		assert.True(t, res.Succeeded())
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, "0", res.Header.Get("Grpc-Status"))
+		assert.JSONEq(t, `{"status":"SERVING"}`, string(res.ResponseBody))
+
+		md := <-received
+		assert.Equal(t, []string{"evt-1"}, md.Get("webhookx-event-id"))
+		assert.Equal(t, []string{"a", "b"}, md.Get("x-foo"))
+	})
+
+	t.Run("should call method resolved from descriptor set", func(t *testing.T) {
+		d := NewGRPCDeliverer(Options{RequestTimeout: time.Second})
+		defer func() { _ = d.Close() }()
+		res := d.Send(context.TODO(), newGRPCRequest(t, addr, `{"service":"webhookx"}`,
+			&GRPCEndpointConfig{Method: "grpc.health.v1.Health/Check", DescriptorSet: &descriptorSet}))
+		assert.NoError(t, res.Error)
+		assert.True(t, res.Succeeded())
+		assert.JSONEq(t, `{"status":"SERVING"}`, string(res.ResponseBody))
+	})
+
+	t.Run("should map gRPC status onto status code", func(t *testing.T) {
+		d := NewGRPCDeliverer(Options{RequestTimeout: time.Second})
+		defer func() { _ = d.Close() }()
+		res := d.Send(context.TODO(), newGRPCRequest(t, addr, `{"service":"unknown"}`,
+			&GRPCEndpointConfig{Method: "grpc.health.v1.Health/Check", DescriptorSet: &descriptorSet}))
+		assert.NoError(t, res.Error)
+		assert.False(t, res.Succeeded())
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, "5", res.Header.Get("Grpc-Status"))
+		assert.Equal(t, "unknown service", res.Header.Get("Grpc-Message"))
+	})
+
+	t.Run("should fail when request body is not mapped into request message", func(t *testing.T) {
+		d := NewGRPCDeliverer(Options{RequestTimeout: time.Second})
+		defer func() { _ = d.Close() }()
+		res := d.Send(context.TODO(), newGRPCRequest(t, addr, `{"service":1}`,
+			&GRPCEndpointConfig{Method: "grpc.health.v1.Health/Check", DescriptorSet: &descriptorSet}))
+		assert.ErrorContains(t, res.Error, "failed to map request body into grpc.health.v1.HealthCheckRequest")
+	})
+
+	t.Run("should fail when method is not found", func(t *testing.T) {
+		d := NewGRPCDeliverer(Options{RequestTimeout: time.Second})
+		defer func() { _ = d.Close() }()
+		res := d.Send(context.TODO(), newGRPCRequest(t, addr, `{}`,
+			&GRPCEndpointConfig{Method: "grpc.health.v1.Health/Unknown"}))
+		assert.EqualError(t, res.Error, "failed to resolve method via server reflection: method Unknown not found in service grpc.health.v1.Health")
+		res = d.Send(context.TODO(), newGRPCRequest(t, addr, `{}`,
+			&GRPCEndpointConfig{Method: "grpc.health.v1.Health/Watch", DescriptorSet: &descriptorSet}))
+		assert.EqualError(t, res.Error, "method grpc.health.v1.Health.Watch is not unary")
+	})
+
+	t.Run("should be unavailable when server is unreachable", func(t *testing.T) {
+		d := NewGRPCDeliverer(Options{RequestTimeout: time.Second})
+		defer func() { _ = d.Close() }()
+		res := d.Send(context.TODO(), newGRPCRequest(t, "127.0.0.1:1", `{}`,
+			&GRPCEndpointConfig{Method: "grpc.health.v1.Health/Check", DescriptorSet: &descriptorSet}))
+		assert.NoError(t, res.Error)
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -29,11 +30,20 @@ var DefaultTLSConfig *tls.Config = nil
 
 type contextKey struct{}
 
+const (
+	// maxTLSClients is the maximum number of cached clients of distinct TLS options
+	maxTLSClients = 1024
+	tlsClientTTL  = time.Hour
+)
+
 // HTTPDeliverer delivers via HTTP
 type HTTPDeliverer struct {
 	log    *zap.SugaredLogger
 	client *http.Client
 	opts   Options
+
+	// tlsClients caches one client for each distinct TLS options
+	tlsClients *expirable.LRU[string, *http.Client]
 }
 
 func restrictedDialFunc(acl *ACL) func(context.Context, string, string) (net.Conn, error) {
@@ -83,6 +93,9 @@ type Options struct {
 	RequestTimeout time.Duration
 	AclOptions     *AclOptions
 	ProxyOptions   *ProxyOptions
+	// MaxResponseBodySize is the maximum size (in bytes) of response body to read, the rest is discarded.
+	// Zero means no limit.
+	MaxResponseBodySize int64
 }
 
 func NewHTTPDeliverer(opts Options) *HTTPDeliverer {
@@ -102,6 +115,9 @@ func NewHTTPDeliverer(opts Options) *HTTPDeliverer {
 		log:    opts.Logger,
 		client: client,
 		opts:   opts,
+		tlsClients: expirable.NewLRU[string, *http.Client](maxTLSClients, func(_ string, c *http.Client) {
+			c.CloseIdleConnections()
+		}, tlsClientTTL),
 	}
 }
 
@@ -196,6 +212,34 @@ func (d *HTTPDeliverer) setupProxy(opts ProxyOptions) error {
 	return nil
 }
 
+// Client returns the client of default TLS options, which applies the proxy and ACL settings
+func (d *HTTPDeliverer) Client() *http.Client {
+	return d.client
+}
+
+// getClient returns the client for the TLS options, the transport of client is cloned from
+// the default one so that the proxy and ACL settings apply as well.
+func (d *HTTPDeliverer) getClient(opts *TLSOptions) (*http.Client, error) {
+	if opts == nil {
+		return d.client, nil
+	}
+
+	key := opts.Key()
+	if client, ok := d.tlsClients.Get(key); ok {
+		return client, nil
+	}
+
+	config, err := opts.Config()
+	if err != nil {
+		return nil, err
+	}
+	transport := d.client.Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	client := &http.Client{Transport: transport}
+	d.tlsClients.Add(key, client)
+	return client, nil
+}
+
 func timing(fn func()) time.Duration {
 	start := time.Now()
 	fn()
@@ -221,10 +265,16 @@ func (d *HTTPDeliverer) Send(ctx context.Context, request *Request) *Response {
 		Request: request,
 	}
 
+	client, err := d.getClient(request.TLS)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+
 	t := timing(func() {
 		ctx = context.WithValue(ctx, contextKey{}, res)
 		r := request.Request.WithContext(ctx)
-		response, err := d.client.Do(r)
+		response, err := client.Do(r)
 		if err != nil {
 			res.Error = err
 			return
@@ -232,13 +282,14 @@ func (d *HTTPDeliverer) Send(ctx context.Context, request *Request) *Response {
 		res.StatusCode = response.StatusCode
 		res.Header = response.Header
 
-		body, err := io.ReadAll(response.Body)
+		body, truncated, err := readBody(response.Body, d.opts.MaxResponseBodySize)
+		_ = response.Body.Close()
 		if err != nil {
 			res.Error = err
 			return
 		}
-		_ = response.Body.Close()
 		res.ResponseBody = body
+		res.ResponseBodyTruncated = truncated
 	})
 
 	res.Latancy = t
@@ -79,3 +79,29 @@ func Test(t *testing.T) {
 	})
 
 }
+
+func TestHTTPDelivererMaxResponseBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		max       int64
+		body      string
+		truncated bool
+	}{
+		{0, "0123456789", false},
+		{10, "0123456789", false},
+		{4, "0123", true},
+	}
+	for _, test := range tests {
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second, MaxResponseBodySize: test.max})
+		r, err := http.NewRequest("POST", server.URL, nil)
+		assert.NoError(t, err)
+		res := deliverer.Send(context.Background(), &Request{Request: r})
+		assert.NoError(t, res.Error)
+		assert.Equal(t, test.body, string(res.ResponseBody))
+		assert.Equal(t, test.truncated, res.ResponseBodyTruncated)
+	}
+}
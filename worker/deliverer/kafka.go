@@ -0,0 +1,178 @@
+package deliverer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+const EndpointTypeKafka entities.EndpointType = "kafka"
+
+type KafkaEndpointConfig struct {
+	Topic string `json:"topic"`
+	// Key is the JSON pointer of message key in message value, the messages are keyed by event id if nil
+	Key *string `json:"key"`
+}
+
+// KafkaDeliverer delivers via Kafka, the request url is the bootstrap brokers (e.g. kafka://host1:9092,host2:9092)
+// and the credentials in url authenticate with SASL/PLAIN.
+type KafkaDeliverer struct {
+	messageDeliverer
+
+	writers *expirable.LRU[string, *kafka.Writer]
+}
+
+func NewKafkaDeliverer(opts Options) *KafkaDeliverer {
+	return &KafkaDeliverer{
+		messageDeliverer: newMessageDeliverer(opts),
+		writers: expirable.NewLRU[string, *kafka.Writer](maxBrokerClients, func(_ string, w *kafka.Writer) {
+			_ = w.Close()
+		}, brokerClientTTL),
+	}
+}
+
+func (d *KafkaDeliverer) getWriter(request *Request, topic string) (*kafka.Writer, error) {
+	key := clientKey(request, topic)
+	if w, ok := d.writers.Get(key); ok {
+		return w, nil
+	}
+
+	config, err := tlsConfig(request)
+	if err != nil {
+		return nil, err
+	}
+	u := request.Request.URL
+	// the brokers discovered from cluster metadata are dialed by the dial function restricted by ACL as well
+	transport := &kafka.Transport{
+		Dial:        d.dial,
+		DialTimeout: dialTimeout,
+		TLS:         config,
+	}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		transport.SASL = plain.Mechanism{Username: u.User.Username(), Password: password}
+	}
+	w := &kafka.Writer{
+		Addr:         kafka.TCP(strings.Split(u.Host, ",")...),
+		Topic:        topic,
+		Transport:    transport,
+		MaxAttempts:  3,
+		BatchTimeout: 10 * time.Millisecond,
+		RequiredAcks: kafka.RequireAll,
+	}
+	d.writers.Add(key, w)
+	return w, nil
+}
+
+// messageKey returns the message key that the JSON pointer resolves in message value
+func messageKey(pointer *string, value []byte, def string) []byte {
+	if pointer == nil {
+		return []byte(def)
+	}
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return []byte(def)
+	}
+	v, ok := utils.LookupJSONPointer(v, *pointer)
+	if !ok || v == nil {
+		return []byte(def)
+	}
+	if str, ok := v.(string); ok {
+		return []byte(str)
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func (d *KafkaDeliverer) Send(ctx context.Context, request *Request) *Response {
+	ctx, res, done := d.start(ctx, "kafka.send", request)
+	defer done()
+
+	var config KafkaEndpointConfig
+	if res.Error = decodeConfig(request, EndpointTypeKafka, &config); res.Error != nil {
+		return res
+	}
+
+	brokers := strings.Split(request.Request.URL.Host, ",")
+	if res.Error = d.checkACL(ctx, res, brokers...); res.Error != nil {
+		return res
+	}
+
+	w, err := d.getWriter(request, config.Topic)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+
+	headers := make([]kafka.Header, 0, len(request.Request.Header))
+	for name, value := range messageHeaders(request.Request.Header) {
+		headers = append(headers, kafka.Header{Key: name, Value: []byte(value)})
+	}
+	res.Error = w.WriteMessages(ctx, kafka.Message{
+		Key:     messageKey(config.Key, request.Body, request.Request.Header.Get("Webhookx-Event-Id")),
+		Value:   request.Body,
+		Headers: headers,
+	})
+	var netErr net.Error
+	if errors.As(res.Error, &netErr) && netErr.Timeout() {
+		res.Error = errors.Join(context.DeadlineExceeded, res.Error)
+	}
+	res.ErrorCode = kafkaErrorCode(res.Error)
+	return res
+}
+
+// kafkaErrorCode returns the error code of the error that fails to write, so that the retry policy can target
+// the broker and auth errors. It returns an empty string for the other errors.
+func kafkaErrorCode(err error) entities.AttemptErrorCode {
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) {
+		for _, e := range writeErrs {
+			if e != nil {
+				return kafkaErrorCode(e)
+			}
+		}
+		return ""
+	}
+
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) {
+		switch kafkaErr {
+		case kafka.SASLAuthenticationFailed,
+			kafka.UnsupportedSASLMechanism,
+			kafka.IllegalSASLState,
+			kafka.TopicAuthorizationFailed,
+			kafka.ClusterAuthorizationFailed:
+			return entities.AttemptErrorCodeAuthFailed
+		case kafka.LeaderNotAvailable,
+			kafka.NotLeaderForPartition,
+			kafka.BrokerNotAvailable,
+			kafka.RequestTimedOut,
+			kafka.NetworkException,
+			kafka.NotEnoughReplicas,
+			kafka.NotEnoughReplicasAfterAppend,
+			kafka.KafkaStorageError:
+			return entities.AttemptErrorCodeBrokerUnavailable
+		}
+		return ""
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return entities.AttemptErrorCodeBrokerUnavailable
+	}
+	return ""
+}
+
+func (d *KafkaDeliverer) Close() error {
+	d.writers.Purge()
+	return nil
+}
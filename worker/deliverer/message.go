@@ -0,0 +1,128 @@
+package deliverer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// dialTimeout is the timeout of connecting to message brokers
+	dialTimeout = 10 * time.Second
+	// maxBrokerClients is the maximum number of cached clients of distinct brokers
+	maxBrokerClients = 1024
+	brokerClientTTL  = time.Hour
+)
+
+// messageDeliverer holds what the non-HTTP deliverers have in common,
+// the connections to brokers and servers are restricted by ACL as HTTP requests.
+type messageDeliverer struct {
+	opts Options
+	acl  *ACL
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func newMessageDeliverer(opts Options) messageDeliverer {
+	d := messageDeliverer{
+		opts: opts,
+		dial: (&net.Dialer{Timeout: dialTimeout}).DialContext,
+	}
+	if opts.AclOptions != nil {
+		d.acl = NewACL(*opts.AclOptions)
+		d.dial = restrictedDialFunc(d.acl)
+	}
+	return d
+}
+
+// start starts the delivery of request, the returned function must be called once the delivery finished
+func (d *messageDeliverer) start(ctx context.Context, name string, request *Request, opts ...trace.SpanStartOption) (context.Context, *Response, func()) {
+	if len(opts) == 0 {
+		opts = []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindProducer),
+			trace.WithAttributes(attribute.String("messaging.system", strings.TrimSuffix(name, ".send"))),
+		}
+	}
+	ctx, span := tracing.Start(ctx, name, opts...)
+
+	timeout := request.Timeout
+	if timeout == 0 {
+		timeout = d.opts.RequestTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	res := &Response{
+		Request: request,
+	}
+	start := time.Now()
+	return ctx, res, func() {
+		res.Latancy = time.Since(start)
+		cancel()
+		span.End()
+	}
+}
+
+// checkACL checks the broker hosts against ACL before connecting to them
+func (d *messageDeliverer) checkACL(ctx context.Context, res *Response, hosts ...string) error {
+	if d.acl == nil {
+		return nil
+	}
+	for _, host := range hosts {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !d.acl.AllowHost(host) {
+			res.ACL.Denied = true
+			return fmt.Errorf("request to %s is denied", host)
+		}
+		ips, err := DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return err
+		}
+		allowed := false
+		for _, ip := range ips {
+			if d.acl.AllowIP(ip) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			res.ACL.Denied = true
+			return fmt.Errorf("request to %s(ip=%s) is denied", host, ips[0])
+		}
+	}
+	return nil
+}
+
+// tlsConfig returns the tls.Config of request, or nil if the request has no TLS options
+func tlsConfig(request *Request) (*tls.Config, error) {
+	if request.TLS == nil {
+		return nil, nil
+	}
+	return request.TLS.Config()
+}
+
+// clientKey returns the key of cached client, the requests of same broker and TLS options share one client
+func clientKey(request *Request, parts ...string) string {
+	key := request.Request.URL.String() + "|" + strings.Join(parts, "|")
+	if request.TLS != nil {
+		key += "|" + request.TLS.Key()
+	}
+	return key
+}
+
+// messageHeaders returns the request headers as message headers, the multiple values are joined with comma
+func messageHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		headers[name] = strings.Join(values, ",")
+	}
+	return headers
+}
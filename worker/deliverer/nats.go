@@ -0,0 +1,123 @@
+package deliverer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/nats-io/nats.go"
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+const EndpointTypeNATS entities.EndpointType = "nats"
+
+type NATSEndpointConfig struct {
+	Subject string `json:"subject"`
+	// JetStream publishes the messages to JetStream and waits for the acknowledgement
+	JetStream bool `json:"jetstream"`
+}
+
+// NATSDeliverer delivers via NATS, the request url is the server address (e.g. nats://localhost:4222).
+type NATSDeliverer struct {
+	messageDeliverer
+
+	mux   sync.Mutex
+	conns *expirable.LRU[string, *nats.Conn]
+}
+
+func NewNATSDeliverer(opts Options) *NATSDeliverer {
+	return &NATSDeliverer{
+		messageDeliverer: newMessageDeliverer(opts),
+		conns: expirable.NewLRU[string, *nats.Conn](maxBrokerClients, func(_ string, nc *nats.Conn) {
+			nc.Close()
+		}, brokerClientTTL),
+	}
+}
+
+type natsDialer struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func (d *natsDialer) Dial(network, address string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	return d.dial(ctx, network, address)
+}
+
+func (d *NATSDeliverer) getConn(request *Request) (*nats.Conn, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	key := clientKey(request)
+	if nc, ok := d.conns.Get(key); ok && !nc.IsClosed() {
+		return nc, nil
+	}
+
+	options := []nats.Option{
+		nats.Name("webhookx"),
+		nats.Timeout(dialTimeout),
+		nats.SetCustomDialer(&natsDialer{dial: d.dial}),
+	}
+	config, err := tlsConfig(request)
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		options = append(options, nats.Secure(config))
+	}
+	nc, err := nats.Connect(request.Request.URL.String(), options...)
+	if err != nil {
+		return nil, err
+	}
+	d.conns.Add(key, nc)
+	return nc, nil
+}
+
+func (d *NATSDeliverer) Send(ctx context.Context, request *Request) *Response {
+	ctx, res, done := d.start(ctx, "nats.send", request)
+	defer done()
+
+	var config NATSEndpointConfig
+	if res.Error = decodeConfig(request, EndpointTypeNATS, &config); res.Error != nil {
+		return res
+	}
+
+	if res.Error = d.checkACL(ctx, res, request.Request.URL.Host); res.Error != nil {
+		return res
+	}
+
+	nc, err := d.getConn(request)
+	if err != nil {
+		res.Error = err
+		return res
+	}
+
+	msg := &nats.Msg{
+		Subject: config.Subject,
+		Data:    request.Body,
+		Header:  nats.Header(request.Request.Header.Clone()),
+	}
+	if config.JetStream {
+		js, err := nc.JetStream()
+		if err != nil {
+			res.Error = err
+			return res
+		}
+		_, res.Error = js.PublishMsg(msg, nats.Context(ctx))
+	} else {
+		if res.Error = nc.PublishMsg(msg); res.Error == nil {
+			res.Error = nc.FlushWithContext(ctx)
+		}
+	}
+	if errors.Is(res.Error, nats.ErrTimeout) {
+		res.Error = errors.Join(context.DeadlineExceeded, res.Error)
+	}
+	return res
+}
+
+func (d *NATSDeliverer) Close() error {
+	d.conns.Purge()
+	return nil
+}
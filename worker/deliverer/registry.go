@@ -0,0 +1,201 @@
+package deliverer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+// Registration is the registration of deliverer of an endpoint type, it validates the endpoints of type
+// and creates the deliverer.
+type Registration struct {
+	// Schemes is the allowed URL schemes of request url, any scheme is allowed if empty
+	Schemes []string
+	// ValidateConfig validates the config of endpoint type (the value of config.<type>), the config is optional if nil.
+	// The fields of returned *errs.ValidateError are reported as the fields of config.
+	ValidateConfig func(config map[string]interface{}) error
+	// Factory creates the deliverer when an endpoint of type is delivered for the first time,
+	// the deliverer is closed when the worker stops if it implements io.Closer.
+	Factory func(opts Options) (Deliverer, error)
+}
+
+var mux sync.RWMutex
+var registry = map[entities.EndpointType]*Registration{}
+
+func init() {
+	RegisterDeliverer(entities.EndpointTypeHTTP, Registration{
+		Factory: func(opts Options) (Deliverer, error) {
+			d := NewHTTPDeliverer(opts)
+			if err := d.Setup(); err != nil {
+				return nil, err
+			}
+			return d, nil
+		},
+	})
+	RegisterDeliverer(EndpointTypeKafka, Registration{
+		Schemes:        []string{"kafka"},
+		ValidateConfig: decodes[KafkaEndpointConfig],
+		Factory: func(opts Options) (Deliverer, error) {
+			return NewKafkaDeliverer(opts), nil
+		},
+	})
+	RegisterDeliverer(EndpointTypeNATS, Registration{
+		Schemes:        []string{"nats", "tls"},
+		ValidateConfig: decodes[NATSEndpointConfig],
+		Factory: func(opts Options) (Deliverer, error) {
+			return NewNATSDeliverer(opts), nil
+		},
+	})
+	RegisterDeliverer(EndpointTypeAMQP, Registration{
+		Schemes:        []string{"amqp", "amqps"},
+		ValidateConfig: decodes[AMQPEndpointConfig],
+		Factory: func(opts Options) (Deliverer, error) {
+			return NewAMQPDeliverer(opts), nil
+		},
+	})
+	RegisterDeliverer(EndpointTypeGRPC, Registration{
+		Schemes: []string{"grpc", "grpcs"},
+		ValidateConfig: func(config map[string]interface{}) error {
+			var c GRPCEndpointConfig
+			if err := utils.MapToStruct(config, &c); err != nil {
+				return err
+			}
+			if fields := validateGRPCConfig(&c); fields != nil {
+				e := errs.NewValidateError(errs.ErrRequestValidation)
+				e.Fields = fields
+				return e
+			}
+			return nil
+		},
+		Factory: func(opts Options) (Deliverer, error) {
+			return NewGRPCDeliverer(opts), nil
+		},
+	})
+}
+
+// decodes validates that the config is decoded into T, the fields are validated by the schema
+func decodes[T any](config map[string]interface{}) error {
+	var v T
+	return utils.MapToStruct(config, &v)
+}
+
+// RegisterDeliverer registers the deliverer of endpoint type, so that the endpoints of typ are accepted
+// and delivered by the deliverer. It should be called before the application starts, e.g. in init function.
+func RegisterDeliverer(typ entities.EndpointType, r Registration) {
+	mux.Lock()
+	defer mux.Unlock()
+	if _, ok := registry[typ]; ok {
+		panic(fmt.Sprintf("deliverer '%s' already registered", typ))
+	}
+	if r.Factory == nil {
+		panic(fmt.Sprintf("deliverer '%s' has no factory", typ))
+	}
+	registry[typ] = &r
+}
+
+func lookup(typ entities.EndpointType) *Registration {
+	if typ == "" {
+		typ = entities.EndpointTypeHTTP
+	}
+	mux.RLock()
+	defer mux.RUnlock()
+	return registry[typ]
+}
+
+// Validate validates the type, the scheme of request url and the config of endpoint against the registration
+// of endpoint type, it is an entities.EndpointValidator.
+func Validate(m *entities.Endpoint) map[string]interface{} {
+	r := lookup(m.Type)
+	if r == nil {
+		return map[string]interface{}{"type": fmt.Sprintf("unknown endpoint type '%s'", m.Type)}
+	}
+
+	fields := make(map[string]interface{})
+	if len(r.Schemes) > 0 {
+		if u, err := url.Parse(m.Request.URL); err != nil || !slices.Contains(r.Schemes, u.Scheme) {
+			fields["request"] = map[string]interface{}{
+				"url": fmt.Sprintf("scheme must be one of [%s] for %s endpoint", strings.Join(r.Schemes, ", "), m.Type),
+			}
+		}
+	}
+	if r.ValidateConfig != nil {
+		if config := m.Config[string(m.Type)]; config == nil {
+			fields["config"] = map[string]interface{}{string(m.Type): "required field missing"}
+		} else if err := r.ValidateConfig(config); err != nil {
+			var e *errs.ValidateError
+			if errors.As(err, &e) {
+				fields["config"] = map[string]interface{}{string(m.Type): e.Fields}
+			} else {
+				fields["config"] = map[string]interface{}{string(m.Type): err.Error()}
+			}
+		}
+	}
+	return fields
+}
+
+// Deliverers holds the deliverers of endpoint types, the deliverer of a type is created on first use
+type Deliverers struct {
+	opts       Options
+	mux        sync.RWMutex
+	deliverers map[entities.EndpointType]Deliverer
+}
+
+func NewDeliverers(opts Options) *Deliverers {
+	return &Deliverers{
+		opts:       opts,
+		deliverers: make(map[entities.EndpointType]Deliverer),
+	}
+}
+
+// Get returns the deliverer of endpoint type, the endpoints without type are HTTP endpoints
+func (d *Deliverers) Get(typ entities.EndpointType) (Deliverer, error) {
+	if typ == "" {
+		typ = entities.EndpointTypeHTTP
+	}
+
+	d.mux.RLock()
+	deliverer, ok := d.deliverers[typ]
+	d.mux.RUnlock()
+	if ok {
+		return deliverer, nil
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if deliverer, ok := d.deliverers[typ]; ok {
+		return deliverer, nil
+	}
+	r := lookup(typ)
+	if r == nil {
+		return nil, fmt.Errorf("unknown endpoint type '%s'", typ)
+	}
+	deliverer, err := r.Factory(d.opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s deliverer: %w", typ, err)
+	}
+	d.deliverers[typ] = deliverer
+	return deliverer, nil
+}
+
+// Close closes the created deliverers that implement io.Closer
+func (d *Deliverers) Close() error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	var closeErrs []error
+	for typ, deliverer := range d.deliverers {
+		if closer, ok := deliverer.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				closeErrs = append(closeErrs, fmt.Errorf("failed to close %s deliverer: %w", typ, err))
+			}
+		}
+	}
+	return errors.Join(closeErrs...)
+}
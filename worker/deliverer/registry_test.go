@@ -0,0 +1,108 @@
+package deliverer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+type testDeliverer struct {
+	config map[string]interface{}
+}
+
+func (d *testDeliverer) Send(_ context.Context, request *Request) *Response {
+	d.config = request.Config["registry-test"]
+	return &Response{Request: request, StatusCode: 202}
+}
+
+func TestRegisterDeliverer(t *testing.T) {
+	d := &testDeliverer{}
+	created := 0
+	RegisterDeliverer("registry-test", Registration{
+		Schemes: []string{"test"},
+		ValidateConfig: func(config map[string]interface{}) error {
+			if _, ok := config["queue"].(string); !ok {
+				return errors.New("queue is required")
+			}
+			return nil
+		},
+		Factory: func(opts Options) (Deliverer, error) {
+			created++
+			return d, nil
+		},
+	})
+
+	assert.PanicsWithValue(t, "deliverer 'registry-test' already registered", func() {
+		RegisterDeliverer("registry-test", Registration{Factory: func(opts Options) (Deliverer, error) { return d, nil }})
+	})
+	assert.PanicsWithValue(t, "deliverer 'http' already registered", func() {
+		RegisterDeliverer(entities.EndpointTypeHTTP, Registration{Factory: func(opts Options) (Deliverer, error) { return d, nil }})
+	})
+
+	t.Run("should validate endpoints of registered type", func(t *testing.T) {
+		var endpoint entities.Endpoint
+		err := json.Unmarshal([]byte(`{"type":"registry-test","request":{"url":"test://localhost"},"config":{"kafka":null,"registry-test":{"queue":"events"}}}`), &endpoint)
+		assert.NoError(t, err)
+		assert.NoError(t, endpoint.Validate(Validate))
+		assert.Equal(t, map[string]interface{}{"queue": "events"}, endpoint.Config["registry-test"])
+
+		endpoint.Request.URL = "http://localhost"
+		endpoint.Config["registry-test"] = map[string]interface{}{}
+		assert.Equal(t, map[string]interface{}{
+			"config":  map[string]interface{}{"registry-test": "queue is required"},
+			"request": map[string]interface{}{"url": "scheme must be one of [test] for registry-test endpoint"},
+		}, Validate(&endpoint))
+
+		endpoint.Config = nil
+		assert.Equal(t, map[string]interface{}{"registry-test": "required field missing"}, Validate(&endpoint)["config"])
+
+		endpoint.Type = "unknown"
+		assert.Equal(t, map[string]interface{}{"type": "unknown endpoint type 'unknown'"}, Validate(&endpoint))
+	})
+
+	t.Run("should validate endpoints of built-in types", func(t *testing.T) {
+		endpoint := entities.Endpoint{Type: EndpointTypeGRPC}
+		endpoint.Request.URL = "grpc://localhost:50051"
+		endpoint.Config = entities.EndpointConfig{"grpc": {"method": "grpc.health.v1.Health/Check"}}
+		assert.Empty(t, Validate(&endpoint))
+
+		endpoint.Config["grpc"]["method"] = "Check"
+		assert.Equal(t, map[string]interface{}{
+			"config": map[string]interface{}{"grpc": map[string]interface{}{"method": "method must be in the form of package.Service/Method"}},
+		}, Validate(&endpoint))
+
+		endpoint = entities.Endpoint{}
+		endpoint.Request.URL = "http://localhost"
+		assert.Empty(t, Validate(&endpoint))
+	})
+
+	t.Run("should create deliverers on first use", func(t *testing.T) {
+		deliverers := NewDeliverers(Options{})
+		for _, typ := range []entities.EndpointType{"", EndpointTypeKafka, EndpointTypeNATS, EndpointTypeAMQP, EndpointTypeGRPC} {
+			deliverer, err := deliverers.Get(typ)
+			assert.NoError(t, err)
+			assert.NotNil(t, deliverer, typ)
+		}
+
+		assert.Equal(t, 0, created)
+		deliverer, err := deliverers.Get("registry-test")
+		assert.NoError(t, err)
+		assert.Same(t, d, deliverer)
+		_, err = deliverers.Get("registry-test")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created)
+
+		_, err = deliverers.Get("unknown")
+		assert.EqualError(t, err, "unknown endpoint type 'unknown'")
+
+		request := newMessageRequest(t, "test://localhost", entities.EndpointConfig{"registry-test": {"queue": "events"}})
+		res := deliverer.Send(context.TODO(), request)
+		assert.True(t, res.Succeeded())
+		assert.Equal(t, map[string]interface{}{"queue": "events"}, d.config)
+		assert.NoError(t, deliverers.Close())
+	})
+}
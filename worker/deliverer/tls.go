@@ -0,0 +1,60 @@
+package deliverer
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// TLSOptions is the TLS settings of a request, the certificates and key are PEM encoded
+type TLSOptions struct {
+	ClientCert         string
+	ClientKey          string
+	CACert             string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// Key returns the digest of options, the requests of identical options share one transport
+func (o *TLSOptions) Key() string {
+	h := sha256.New()
+	for _, v := range []string{o.ClientCert, o.ClientKey, o.CACert, o.ServerName, strconv.FormatBool(o.InsecureSkipVerify)} {
+		h.Write([]byte(strconv.Itoa(len(v))))
+		h.Write([]byte(":"))
+		h.Write([]byte(v))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Config builds the tls.Config from options
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{}
+	if DefaultTLSConfig != nil {
+		config = DefaultTLSConfig.Clone()
+	}
+	if o.ServerName != "" {
+		config.ServerName = o.ServerName
+	}
+	if o.InsecureSkipVerify {
+		config.InsecureSkipVerify = true
+	}
+	if o.ClientCert != "" || o.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(o.ClientCert), []byte(o.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if o.CACert != "" {
+		cp := x509.NewCertPool()
+		if !cp.AppendCertsFromPEM([]byte(o.CACert)) {
+			return nil, errors.New("failed to append ca certificate to pool")
+		}
+		config.RootCAs = cp
+	}
+	return config, nil
+}
@@ -0,0 +1,155 @@
+package deliverer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type certificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+func newCertificate(t *testing.T, template *x509.Certificate, parent *certificate) *certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return &certificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func TestTLS(t *testing.T) {
+	ca := newCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	serverCert := newCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "server"},
+		DNSNames:    []string{"receiver.local"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	clientCert := newCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "client"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	pair, err := tls.X509KeyPair([]byte(serverCert.certPEM), []byte(serverCert.keyPEM))
+	assert.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	send := func(d *HTTPDeliverer, opts *TLSOptions) *Response {
+		r, err := http.NewRequest("GET", server.URL, nil)
+		assert.NoError(t, err)
+		return d.Send(context.Background(), &Request{Request: r, TLS: opts})
+	}
+
+	t.Run("should present client certificate", func(t *testing.T) {
+		d := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 5})
+		opts := &TLSOptions{
+			ClientCert: clientCert.certPEM,
+			ClientKey:  clientCert.keyPEM,
+			CACert:     ca.certPEM,
+		}
+		res := send(d, opts)
+		assert.NoError(t, res.Error)
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, "client", string(res.ResponseBody))
+
+		// the client is cached
+		send(d, &TLSOptions{ClientCert: clientCert.certPEM, ClientKey: clientCert.keyPEM, CACert: ca.certPEM})
+		assert.Equal(t, 1, d.tlsClients.Len())
+	})
+
+	t.Run("should verify server name", func(t *testing.T) {
+		d := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 5})
+		res := send(d, &TLSOptions{
+			ClientCert: clientCert.certPEM,
+			ClientKey:  clientCert.keyPEM,
+			CACert:     ca.certPEM,
+			ServerName: "receiver.local",
+		})
+		assert.NoError(t, res.Error)
+
+		res = send(d, &TLSOptions{
+			ClientCert: clientCert.certPEM,
+			ClientKey:  clientCert.keyPEM,
+			CACert:     ca.certPEM,
+			ServerName: "unknown.local",
+		})
+		assert.Error(t, res.Error)
+	})
+
+	t.Run("should fail without client certificate", func(t *testing.T) {
+		d := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 5})
+		res := send(d, &TLSOptions{CACert: ca.certPEM})
+		assert.Error(t, res.Error)
+	})
+
+	t.Run("should fail with unknown ca", func(t *testing.T) {
+		d := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 5})
+		res := send(d, &TLSOptions{ClientCert: clientCert.certPEM, ClientKey: clientCert.keyPEM})
+		assert.Error(t, res.Error)
+
+		res = send(d, &TLSOptions{ClientCert: clientCert.certPEM, ClientKey: clientCert.keyPEM, InsecureSkipVerify: true})
+		assert.NoError(t, res.Error)
+		assert.Equal(t, "client", string(res.ResponseBody))
+	})
+
+	t.Run("should fail with invalid certificate", func(t *testing.T) {
+		d := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 5})
+		res := send(d, &TLSOptions{ClientCert: "invalid", ClientKey: "invalid"})
+		assert.EqualError(t, res.Error, "failed to load client certificate: tls: failed to find any PEM data in certificate input")
+
+		res = send(d, &TLSOptions{CACert: "invalid"})
+		assert.EqualError(t, res.Error, "failed to append ca certificate to pool")
+	})
+}
@@ -0,0 +1,205 @@
+package ordering
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/retry"
+)
+
+const (
+	// sequenceTTL is how long an idle sequence is kept, it is only extended when an event is appended
+	sequenceTTL = time.Hour * 24 * 7
+
+	// leaseMargin is added to the longest wait of the head between two deliveries as the lease of head,
+	// it covers the queueing latency, the request timeout and the cooldown of circuit breaker
+	leaseMargin = time.Minute * 10
+)
+
+var (
+	cacheKey      = constants.CacheKey{Name: "ordering", Version: "v1"}
+	headCacheKey  = constants.CacheKey{Name: "ordering_head", Version: "v1"}
+	indexCacheKey = constants.CacheKey{Name: "ordering_index", Version: "v1"}
+
+	// sequenceScript defines the functions of the scripts below.
+	// KEYS: sequence, lease of head, index of ordering keys of endpoint.
+	// ARGV: event id, ordering key, lease (ms), ttl (ms).
+	// The head of sequence holds a lease that is renewed whenever it is acquired, the head that has not
+	// been acquired within its lease is considered lost (e.g. the task was deleted) and evicted.
+	sequenceScript = `
+		local function first()
+			return redis.call('ZRANGE', KEYS[1], 0, 0)[1]
+		end
+
+		local function lease(head)
+			if head then
+				redis.call('SET', KEYS[2], head, 'PX', ARGV[3])
+			else
+				redis.call('DEL', KEYS[2])
+			end
+		end
+
+		-- append appends the event to the tail of sequence if absent, the events are scored by a
+		-- per-sequence counter so that a replayed event never goes ahead of the events already in the sequence
+		local function append()
+			if redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+				return
+			end
+			local tail = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+			local seq = 1
+			if tail[2] then
+				seq = tonumber(tail[2]) + 1
+			end
+			redis.call('ZADD', KEYS[1], seq, ARGV[1])
+			redis.call('HSET', KEYS[3], ARGV[1], ARGV[2])
+			redis.call('PEXPIRE', KEYS[1], ARGV[4])
+			redis.call('PEXPIRE', KEYS[3], ARGV[4])
+			if seq == 1 then
+				lease(ARGV[1])
+			end
+		end
+
+		local function remove(id)
+			local head = first()
+			redis.call('ZREM', KEYS[1], id)
+			redis.call('HDEL', KEYS[3], id)
+			if head == id then
+				lease(first())
+			end
+		end
+	`
+
+	// enqueueScript appends the event to the sequence
+	enqueueScript = redis.NewScript(sequenceScript + `
+		append()
+		return 1
+	`)
+
+	// acquireScript appends the event to the sequence if absent, returns 1 if the event is the head of sequence
+	acquireScript = redis.NewScript(sequenceScript + `
+		append()
+		local head = first()
+		if head == ARGV[1] then
+			lease(head)
+			return 1
+		end
+		local holder = redis.call('GET', KEYS[2])
+		if not holder then
+			remove(head)
+			if first() == ARGV[1] then
+				return 1
+			end
+		elseif holder ~= head then
+			lease(head)
+		end
+		return 0
+	`)
+
+	// releaseScript removes the event from the sequence, the lease is passed on if the event is the head
+	releaseScript = redis.NewScript(sequenceScript + `
+		remove(ARGV[1])
+		return 1
+	`)
+)
+
+// Sequencer keeps the events of an ordered endpoint in sequences (one for each ordering key),
+// only the head of a sequence is allowed to be delivered.
+type Sequencer struct {
+	client *redis.Client
+}
+
+func NewSequencer(client *redis.Client) *Sequencer {
+	return &Sequencer{client: client}
+}
+
+func (s *Sequencer) run(ctx context.Context, script *redis.Script, endpoint *entities.Endpoint, key string, eventId string) *redis.Cmd {
+	keys := []string{
+		cacheKey.Build(endpoint.ID, ":", key),
+		headCacheKey.Build(endpoint.ID, ":", key),
+		indexCacheKey.Build(endpoint.ID),
+	}
+	return script.Run(ctx, s.client, keys, eventId, key, lease(endpoint).Milliseconds(), sequenceTTL.Milliseconds())
+}
+
+// Enqueue appends the event to the sequence of endpoint, the events are sequenced in the order they are enqueued.
+func (s *Sequencer) Enqueue(ctx context.Context, endpoint *entities.Endpoint, event *entities.Event) error {
+	return s.run(ctx, enqueueScript, endpoint, Key(endpoint, event.Data), event.ID).Err()
+}
+
+// Acquire reports whether the event is the head of sequence, the event is appended to the sequence if absent.
+func (s *Sequencer) Acquire(ctx context.Context, endpoint *entities.Endpoint, key string, eventId string) (bool, error) {
+	n, err := s.run(ctx, acquireScript, endpoint, key, eventId).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Release removes the event from the sequence once its delivery finished, so that the next event can be delivered.
+func (s *Sequencer) Release(ctx context.Context, endpoint *entities.Endpoint, key string, eventId string) error {
+	return s.run(ctx, releaseScript, endpoint, key, eventId).Err()
+}
+
+// Remove removes the event from the sequences of endpoint when the ordering key cannot be evaluated,
+// e.g. the event no longer exists.
+func (s *Sequencer) Remove(ctx context.Context, endpoint *entities.Endpoint, eventId string) error {
+	key, err := s.client.HGet(ctx, indexCacheKey.Build(endpoint.ID), eventId).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return err
+	}
+	return s.Release(ctx, endpoint, key, eventId)
+}
+
+// lease returns the lease of head, that is the longest wait of the head between two deliveries
+// (a retry delay or a rate limit period) plus leaseMargin.
+func lease(endpoint *entities.Endpoint) time.Duration {
+	var d time.Duration
+	config := endpoint.Retry.Config
+	switch endpoint.Retry.Strategy {
+	case entities.RetryStrategyExponential:
+		d = time.Duration(utils.DefaultIfZero(config.MaxDelay, int64(retry.DefaultMaxDelay.Seconds()))) * time.Second
+	default:
+		for _, seconds := range config.Attempts {
+			d = max(d, time.Duration(seconds)*time.Second)
+		}
+	}
+	if endpoint.Retry.RetryAfter != nil {
+		d = max(d, time.Duration(endpoint.Retry.RetryAfter.MaxDelay)*time.Second)
+	}
+	if endpoint.RateLimit != nil {
+		d = max(d, time.Duration(endpoint.RateLimit.Period)*time.Second)
+	}
+	return d + leaseMargin
+}
+
+// Key returns the ordering key of event data for the endpoint.
+// It returns an empty string if the endpoint has no key or the key is absent in the event data,
+// the events then share the sequence of endpoint.
+func Key(endpoint *entities.Endpoint, data []byte) string {
+	if endpoint.Ordering == nil || endpoint.Ordering.Key == nil {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return ""
+	}
+	v, ok := utils.LookupJSONPointer(v, *endpoint.Ordering.Key)
+	if !ok || v == nil {
+		return ""
+	}
+	if str, ok := v.(string); ok {
+		return str
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
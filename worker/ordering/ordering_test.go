@@ -0,0 +1,36 @@
+package ordering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+func TestKey(t *testing.T) {
+	data := []byte(`{"customer":{"id":"c1","a/b":1,"m~n":true},"items":[{"sku":"s1"}],"empty":null}`)
+
+	tests := []struct {
+		ordering *entities.Ordering
+		expected string
+	}{
+		{ordering: nil, expected: ""},
+		{ordering: &entities.Ordering{}, expected: ""},
+		{ordering: &entities.Ordering{Key: new("/customer/id")}, expected: "c1"},
+		{ordering: &entities.Ordering{Key: new("/customer/a~1b")}, expected: "1"},
+		{ordering: &entities.Ordering{Key: new("/customer/m~0n")}, expected: "true"},
+		{ordering: &entities.Ordering{Key: new("/items/0/sku")}, expected: "s1"},
+		{ordering: &entities.Ordering{Key: new("/customer")}, expected: `{"a/b":1,"id":"c1","m~n":true}`},
+		{ordering: &entities.Ordering{Key: new("/items/1/sku")}, expected: ""},
+		{ordering: &entities.Ordering{Key: new("/unknown")}, expected: ""},
+		{ordering: &entities.Ordering{Key: new("/empty")}, expected: ""},
+		{ordering: &entities.Ordering{Key: new("customer")}, expected: ""},
+	}
+	for _, test := range tests {
+		endpoint := &entities.Endpoint{Ordering: test.ordering}
+		assert.Equal(t, test.expected, Key(endpoint, data))
+	}
+
+	endpoint := &entities.Endpoint{Ordering: &entities.Ordering{Key: new("/customer/id")}}
+	assert.Equal(t, "", Key(endpoint, []byte("invalid")))
+}
@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+const (
+	DefaultInitialDelay = time.Second
+	DefaultMultiplier   = 2.0
+	DefaultMaxDelay     = time.Hour
+	DefaultMaxAttempts  = 10
+)
+
+// ExponentialStrategyRetry delays attempts by initialDelay * multiplier^(n-1),
+// the first attempt is never delayed.
+type ExponentialStrategyRetry struct {
+	initialDelay time.Duration
+	multiplier   float64
+	maxDelay     time.Duration
+	maxAttempts  int
+	jitter       entities.RetryJitter
+	random       func(n int64) int64
+}
+
+func newExponentialStrategyRetry() *ExponentialStrategyRetry {
+	return &ExponentialStrategyRetry{
+		initialDelay: DefaultInitialDelay,
+		multiplier:   DefaultMultiplier,
+		maxDelay:     DefaultMaxDelay,
+		maxAttempts:  DefaultMaxAttempts,
+		jitter:       entities.RetryJitterNone,
+		random:       rand.Int64N,
+	}
+}
+
+func WithInitialDelay(delay time.Duration) Option {
+	return func(r Retry) {
+		retry := r.(*ExponentialStrategyRetry)
+		retry.initialDelay = delay
+	}
+}
+
+func WithMultiplier(multiplier float64) Option {
+	return func(r Retry) {
+		retry := r.(*ExponentialStrategyRetry)
+		retry.multiplier = multiplier
+	}
+}
+
+func WithMaxDelay(delay time.Duration) Option {
+	return func(r Retry) {
+		retry := r.(*ExponentialStrategyRetry)
+		retry.maxDelay = delay
+	}
+}
+
+func WithMaxAttempts(attempts int) Option {
+	return func(r Retry) {
+		retry := r.(*ExponentialStrategyRetry)
+		retry.maxAttempts = attempts
+	}
+}
+
+func WithJitter(jitter entities.RetryJitter) Option {
+	return func(r Retry) {
+		retry := r.(*ExponentialStrategyRetry)
+		retry.jitter = jitter
+	}
+}
+
+func (r *ExponentialStrategyRetry) NextDelay(attempts int) time.Duration {
+	if attempts > r.maxAttempts {
+		return Stop
+	}
+	if attempts <= 1 {
+		return 0
+	}
+
+	delay := float64(r.initialDelay) * math.Pow(r.multiplier, float64(attempts-2))
+	if delay > float64(r.maxDelay) || math.IsInf(delay, 0) || math.IsNaN(delay) {
+		delay = float64(r.maxDelay)
+	}
+
+	d := int64(delay)
+	switch r.jitter {
+	case entities.RetryJitterFull:
+		d = r.random(d + 1)
+	case entities.RetryJitterEqual:
+		d = d/2 + r.random(d/2+1)
+	}
+	return time.Duration(d)
+}
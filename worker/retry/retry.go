@@ -2,18 +2,22 @@ package retry
 
 import (
 	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/utils"
 )
 
 type Strategy string
 
 const (
-	FixedStrategy   Strategy = "fixed"
-	BackoffStrategy Strategy = "backoff"
+	FixedStrategy       Strategy = "fixed"
+	ExponentialStrategy Strategy = "exponential"
 )
 
 const Stop time.Duration = -1
 
 type Retry interface {
+	// NextDelay returns the delay before the n-th attempt, or Stop when the attempts are exhausted.
 	NextDelay(attempts int) time.Duration
 }
 
@@ -24,8 +28,8 @@ func NewRetry(strategy Strategy, opts ...Option) Retry {
 	switch strategy {
 	case FixedStrategy:
 		retry = newFixedStrategyRetry()
-	case BackoffStrategy:
-		panic("implement me")
+	case ExponentialStrategy:
+		retry = newExponentialStrategyRetry()
 	default:
 		panic("invalid strategy: " + strategy)
 	}
@@ -34,3 +38,20 @@ func NewRetry(strategy Strategy, opts ...Option) Retry {
 	}
 	return retry
 }
+
+// FromEndpoint returns the Retry of the endpoint's retry configuration
+func FromEndpoint(endpoint *entities.Endpoint) Retry {
+	config := endpoint.Retry.Config
+	switch endpoint.Retry.Strategy {
+	case entities.RetryStrategyExponential:
+		return NewRetry(ExponentialStrategy,
+			WithInitialDelay(time.Duration(utils.DefaultIfZero(config.InitialDelay, int64(DefaultInitialDelay.Seconds())))*time.Second),
+			WithMultiplier(utils.DefaultIfZero(config.Multiplier, DefaultMultiplier)),
+			WithMaxDelay(time.Duration(utils.DefaultIfZero(config.MaxDelay, int64(DefaultMaxDelay.Seconds())))*time.Second),
+			WithMaxAttempts(utils.DefaultIfZero(config.MaxAttempts, DefaultMaxAttempts)),
+			WithJitter(utils.DefaultIfZero(config.Jitter, entities.RetryJitterNone)),
+		)
+	default:
+		return NewRetry(FixedStrategy, WithFixedDelay(config.Attempts))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
 )
 
 func TestRetry(t *testing.T) {
@@ -25,3 +26,58 @@ func TestFixedRetryWithOptions(t *testing.T) {
 	assert.Equal(t, time.Second*4, r.NextDelay(4))
 	assert.Equal(t, Stop, r.NextDelay(5))
 }
+
+func TestExponentialRetry(t *testing.T) {
+	r := NewRetry(ExponentialStrategy)
+	assert.Equal(t, time.Duration(0), r.NextDelay(1))
+	assert.Equal(t, time.Second*1, r.NextDelay(2))
+	assert.Equal(t, time.Second*2, r.NextDelay(3))
+	assert.Equal(t, time.Second*4, r.NextDelay(4))
+	assert.Equal(t, time.Second*256, r.NextDelay(10))
+	assert.Equal(t, Stop, r.NextDelay(11))
+}
+
+func TestExponentialRetryWithOptions(t *testing.T) {
+	r := NewRetry(ExponentialStrategy,
+		WithInitialDelay(time.Second*10),
+		WithMultiplier(3),
+		WithMaxDelay(time.Minute),
+		WithMaxAttempts(5),
+	)
+	assert.Equal(t, time.Duration(0), r.NextDelay(1))
+	assert.Equal(t, time.Second*10, r.NextDelay(2))
+	assert.Equal(t, time.Second*30, r.NextDelay(3))
+	assert.Equal(t, time.Minute, r.NextDelay(4))
+	assert.Equal(t, time.Minute, r.NextDelay(5))
+	assert.Equal(t, Stop, r.NextDelay(6))
+}
+
+func TestExponentialRetryJitter(t *testing.T) {
+	full := NewRetry(ExponentialStrategy, WithInitialDelay(time.Second*10), WithJitter(entities.RetryJitterFull))
+	equal := NewRetry(ExponentialStrategy, WithInitialDelay(time.Second*10), WithJitter(entities.RetryJitterEqual))
+	for range 100 {
+		d := full.NextDelay(2)
+		assert.True(t, d >= 0 && d <= time.Second*10)
+		d = equal.NextDelay(2)
+		assert.True(t, d >= time.Second*5 && d <= time.Second*10)
+	}
+}
+
+func TestFromEndpoint(t *testing.T) {
+	endpoint := &entities.Endpoint{}
+	endpoint.Retry.Strategy = entities.RetryStrategyFixed
+	endpoint.Retry.Config.Attempts = []int64{0, 60}
+	r := FromEndpoint(endpoint)
+	assert.Equal(t, time.Duration(0), r.NextDelay(1))
+	assert.Equal(t, time.Minute, r.NextDelay(2))
+	assert.Equal(t, Stop, r.NextDelay(3))
+
+	endpoint.Retry.Strategy = entities.RetryStrategyExponential
+	endpoint.Retry.Config.InitialDelay = 5
+	endpoint.Retry.Config.MaxAttempts = 3
+	r = FromEndpoint(endpoint)
+	assert.Equal(t, time.Duration(0), r.NextDelay(1))
+	assert.Equal(t, time.Second*5, r.NextDelay(2))
+	assert.Equal(t, time.Second*10, r.NextDelay(3))
+	assert.Equal(t, Stop, r.NextDelay(4))
+}
@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/circuitbreaker"
+)
+
+// system event types
+const (
+	EventTypeEndpointDisabled  = "webhookx.endpoint.disabled"
+	EventTypeEndpointRecovered = "webhookx.endpoint.recovered"
+	EventTypeAttemptExhausted  = "webhookx.attempt.exhausted"
+)
+
+type SystemEventEndpoint struct {
+	ID          string  `json:"id"`
+	Name        *string `json:"name"`
+	WorkspaceId string  `json:"workspace_id"`
+}
+
+type EndpointDisabledData struct {
+	Endpoint    SystemEventEndpoint             `json:"endpoint"`
+	State       circuitbreaker.State            `json:"state"`
+	Reason      string                          `json:"reason"`
+	FailureRate float64                         `json:"failure_rate"`
+	Metric      circuitbreaker.TimeBucketMetric `json:"metric"`
+	OpenedAt    types.Time                      `json:"opened_at"`
+}
+
+type EndpointRecoveredData struct {
+	Endpoint SystemEventEndpoint  `json:"endpoint"`
+	State    circuitbreaker.State `json:"state"`
+}
+
+type AttemptExhaustedData struct {
+	Endpoint      SystemEventEndpoint             `json:"endpoint"`
+	AttemptId     string                          `json:"attempt_id"`
+	AttemptNumber int                             `json:"attempt_number"`
+	EventId       string                          `json:"event_id"`
+	EventType     string                          `json:"event_type"`
+	Reason        entities.AttemptExhaustedReason `json:"reason"`
+	ErrorCode     *entities.AttemptErrorCode      `json:"error_code"`
+	DeadLetterId  string                          `json:"dead_letter_id"`
+}
+
+func newSystemEventEndpoint(endpoint *entities.Endpoint) SystemEventEndpoint {
+	return SystemEventEndpoint{
+		ID:          endpoint.ID,
+		Name:        endpoint.Name,
+		WorkspaceId: endpoint.WorkspaceId,
+	}
+}
+
+// emitSystemEvent dispatches a system event about the endpoint into the system workspace.
+// The endpoints of the system workspace do not emit system events, so that a failing
+// alerting endpoint cannot produce events about itself endlessly.
+func (w *Worker) emitSystemEvent(ctx context.Context, endpoint *entities.Endpoint, eventType string, data interface{}) {
+	if w.opts.SystemEventsWorkspace == "" {
+		return
+	}
+
+	workspace, err := w.db.Workspaces.GetWorkspace(ctx, w.opts.SystemEventsWorkspace)
+	if err != nil {
+		w.log.Warnf("failed to get system workspace: %v", err)
+		return
+	}
+	if workspace == nil {
+		w.log.Warnf("system workspace %s not found, skip emitting %s", w.opts.SystemEventsWorkspace, eventType)
+		return
+	}
+	if endpoint.WorkspaceId == workspace.ID {
+		return
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		w.log.Warnf("failed to marshal system event %s: %v", eventType, err)
+		return
+	}
+	event := &entities.Event{
+		ID:         utils.KSUID(),
+		EventType:  eventType,
+		Data:       b,
+		IngestedAt: types.NewTime(time.Now()),
+	}
+	event.WorkspaceId = workspace.ID
+
+	attempts, err := w.opts.Dispatcher.Dispatch(ctx, []*entities.Event{event})
+	if err != nil {
+		w.log.Warnf("failed to dispatch system event %s: %v", eventType, err)
+		return
+	}
+	w.services.Task.ScheduleAttempts(ctx, attempts)
+	w.log.Debugw("system event emitted", "id", event.ID, "event_type", eventType, "endpoint", endpoint.ID)
+}
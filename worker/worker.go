@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -17,10 +18,13 @@ import (
 	"github.com/webhookx-io/webhookx/db"
 	"github.com/webhookx-io/webhookx/db/dao"
 	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/dispatcher"
 	"github.com/webhookx-io/webhookx/mcache"
 	"github.com/webhookx-io/webhookx/pkg/batchqueue"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/pool"
+	"github.com/webhookx-io/webhookx/pkg/secret"
+	"github.com/webhookx-io/webhookx/pkg/secret/reference"
 	"github.com/webhookx-io/webhookx/pkg/stats"
 	"github.com/webhookx-io/webhookx/pkg/taskqueue"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
@@ -34,6 +38,8 @@ import (
 	"github.com/webhookx-io/webhookx/worker/circuitbreaker"
 	"github.com/webhookx-io/webhookx/worker/circuitbreaker/metrics"
 	"github.com/webhookx-io/webhookx/worker/deliverer"
+	"github.com/webhookx-io/webhookx/worker/ordering"
+	"github.com/webhookx-io/webhookx/worker/retry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -41,6 +47,16 @@ import (
 
 const (
 	DefaultDetectInterval = time.Second * 10
+
+	// orderingBlockedWait is how long a task blocked by earlier event waits before trying again
+	orderingBlockedWait = time.Second
+	// concurrencyLimitedWait is how long a task over the concurrency limit waits before trying again
+	concurrencyLimitedWait = time.Second
+	// concurrencyLeaseMargin is added to the request timeout as the lease of a concurrency permit
+	concurrencyLeaseMargin = time.Second * 10
+
+	// RedactedValue replaces the values of redacted headers in attempt details
+	RedactedValue = "[REDACTED]"
 )
 
 var (
@@ -50,8 +66,12 @@ var (
 )
 
 var (
-	ErrRateLimitExceeded = errors.New("rate limit exceeded")
-	ErrTerminated        = errors.New("terminated")
+	ErrRateLimitExceeded  = errors.New("rate limit exceeded")
+	ErrCircuitBreakerOpen = errors.New("circuit breaker is open")
+	ErrOrderingBlocked    = errors.New("blocked by earlier event")
+	ErrConcurrencyLimited = errors.New("concurrency limit exceeded")
+	ErrBatched            = errors.New("batched")
+	ErrTerminated         = errors.New("terminated")
 )
 
 type Worker struct {
@@ -64,11 +84,14 @@ type Worker struct {
 
 	services *services.Services
 
-	deliverer       deliverer.Deliverer
+	deliverers      *deliverer.Deliverers
+	httpClient      *http.Client
 	db              *db.DB
 	pool            *pool.Pool[*taskqueue.TaskMessage]
 	queueRequestLog *batchqueue.BatchQueue[*entities.AttemptDetail]
 	cbm             *circuitbreaker.Manager
+	sequencer       *ordering.Sequencer
+	batcher         *batcher
 }
 
 type Options struct {
@@ -81,6 +104,12 @@ type Options struct {
 	RedisClient           *redis.Client
 	CircuitBreakerManager *circuitbreaker.Manager
 	EnabledDetection      bool
+	Dispatcher            *dispatcher.Dispatcher
+	// SystemEventsWorkspace is the name of workspace that system events are dispatched into,
+	// system events are not emitted if empty.
+	SystemEventsWorkspace string
+	// SecretManager resolves the secret references in endpoint TLS settings
+	SecretManager *secret.SecretManager
 }
 
 func init() {
@@ -108,8 +137,11 @@ func NewWorker(opts Options, services *services.Services) *Worker {
 		services:        services,
 		queueRequestLog: batchqueue.New[*entities.AttemptDetail]("request_log", 1000, 50, time.Millisecond*500),
 		cbm:             opts.CircuitBreakerManager,
+		sequencer:       ordering.NewSequencer(opts.RedisClient),
+		deliverers:      deliverer.NewDeliverers(opts.DelivererOptions),
 	}
 
+	worker.batcher = newBatcher(worker.flushBatch)
 	worker.pool = pool.New[*taskqueue.TaskMessage](
 		opts.PoolSize,
 		opts.PoolConcurrency,
@@ -158,13 +190,16 @@ func (w *Worker) runTask(ctx context.Context, task *taskqueue.TaskMessage) {
 	err := task.UnmarshalData(task.Data)
 	if err != nil {
 		w.log.Errorf("failed to unmarshal task: %v", err)
+		if attempt, err := w.db.Attempts.Get(ctx, task.ID); err == nil && attempt != nil {
+			w.removeOrdering(ctx, attempt.EndpointId, attempt.EventId)
+		}
 		_ = w.services.Task.DeleteTask(ctx, task)
 		return
 	}
 
 	err = w.handleTask(ctx, task)
 	if err != nil {
-		if errors.Is(ErrRateLimitExceeded, err) {
+		if isRescheduled(err) {
 			return
 		}
 		// TODO: delete task when causes error too many times (maxReceiveCount)
@@ -174,6 +209,15 @@ func (w *Worker) runTask(ctx context.Context, task *taskqueue.TaskMessage) {
 	_ = w.services.Task.DeleteTask(ctx, task)
 }
 
+// isRescheduled reports whether the task has been rescheduled or batched rather than handled
+func isRescheduled(err error) bool {
+	return errors.Is(err, ErrRateLimitExceeded) ||
+		errors.Is(err, ErrCircuitBreakerOpen) ||
+		errors.Is(err, ErrOrderingBlocked) ||
+		errors.Is(err, ErrConcurrencyLimited) ||
+		errors.Is(err, ErrBatched)
+}
+
 func (w *Worker) registerEventHandler(bus eventbus.EventBus) {
 	rs := redsync.New(goredis.NewPool(w.opts.RedisClient))
 	bus.ClusteringSubscribe(eventbus.EventEventFanout, func(data []byte) {
@@ -287,12 +331,14 @@ func (w *Worker) Start() error {
 		"consumers": w.opts.PoolConcurrency,
 	}))
 
-	httpDeliverer := deliverer.NewHTTPDeliverer(w.opts.DelivererOptions)
-	err := httpDeliverer.Setup()
+	d, err := w.deliverers.Get(entities.EndpointTypeHTTP)
 	if err != nil {
 		return err
 	}
-	w.deliverer = httpDeliverer
+	if d, ok := d.(*deliverer.HTTPDeliverer); ok {
+		// the outbound plugins make requests (e.g. requesting a token) with the restricted client
+		w.httpClient = d.Client()
+	}
 
 	for range runtime.NumCPU() {
 		w.queueRequestLog.Consume(w.consumeQueue)
@@ -331,10 +377,14 @@ func (w *Worker) Stop(ctx context.Context) error {
 	w.log.Named("pool").Infow("closing pool", "handling", processing.Load())
 	w.pool.Shutdown()
 	w.log.Named("pool").Info("closed pool")
+	w.batcher.Close()
 	w.queueRequestLog.Close()
 	if err := w.cbm.Stop(ctx); err != nil {
 		return fmt.Errorf("failed to stop circuitbreaker manager: %w", err)
 	}
+	if err := w.deliverers.Close(); err != nil {
+		w.log.Warn(err)
+	}
 	w.log.Info("exit")
 
 	return nil
@@ -356,6 +406,7 @@ func (w *Worker) loadPending(ctx context.Context) error {
 		case <-w.ctx.Done():
 			return nil
 		default:
+			var canceled []*entities.Attempt
 			err := w.db.TX(context.TODO(), func(ctx context.Context) error {
 				maxScheduledAt := time.Now().Add(constants.TaskQueuePreScheduleTimeWindow)
 				attempts, err := w.db.Attempts.ListUnqueuedForUpdate(ctx, maxScheduledAt, batchSize)
@@ -376,6 +427,7 @@ func (w *Worker) loadPending(ctx context.Context) error {
 						if err != nil {
 							return err
 						}
+						canceled = append(canceled, at)
 						continue
 					}
 					at.Event = event
@@ -393,6 +445,9 @@ func (w *Worker) loadPending(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+			for _, at := range canceled {
+				w.removeOrdering(ctx, at.EndpointId, at.EventId)
+			}
 		}
 
 		if done {
@@ -402,7 +457,7 @@ func (w *Worker) loadPending(ctx context.Context) error {
 	return nil
 }
 
-func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) error {
+func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) (err error) {
 	data := task.Data.(*taskqueue.MessageData)
 
 	// validate endpoint
@@ -412,13 +467,59 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 		return err
 	}
 
+	// terminated reports whether the attempt reached a terminal state (succeeded, canceled or exhausted)
+	terminated := false
+	if endpoint != nil && endpoint.Ordering != nil {
+		key := ordering.Key(endpoint, []byte(data.Event))
+		if err := w.acquireOrdering(ctx, task, endpoint, key); err != nil {
+			return err
+		}
+		defer func() {
+			// the next event is unblocked once the attempt is terminated, the head is kept while the
+			// task stays queued (e.g. retried, rescheduled or failed with an error) so that it comes first
+			if terminated {
+				if err := w.sequencer.Release(ctx, endpoint, key, data.EventID); err != nil {
+					w.log.Warnf("failed to release ordering of event %s: %v", data.EventID, err)
+				}
+			}
+		}()
+	}
+
 	if err := w.validateEndpoint(ctx, task, endpoint); err != nil {
 		if errors.Is(err, ErrTerminated) {
+			terminated = true
 			return nil
 		}
 		return err
 	}
 
+	if endpoint.IsBatch() {
+		// the task is deleted once the batch is delivered
+		// the rate limit is charged once the batch is flushed
+		w.batcher.Add(endpoint, task)
+		return ErrBatched
+	}
+
+	if err := w.acquireRateLimit(ctx, task, endpoint); err != nil {
+		return err
+	}
+
+	if endpoint.MaxConcurrency != nil {
+		if err := w.acquireConcurrency(ctx, task, endpoint); err != nil {
+			return err
+		}
+		defer func() {
+			if err := w.services.Semaphore.Release(ctx, endpoint.ID, task.ID); err != nil {
+				w.log.Warnf("failed to release concurrency of endpoint %s: %v", endpoint.ID, err)
+			}
+		}()
+	}
+
+	permit, err := w.acquireCircuitBreaker(ctx, task, endpoint)
+	if err != nil {
+		return err
+	}
+
 	r, err := newRequestFromEndpoint(endpoint)
 	if err != nil {
 		// TODO: optimize error
@@ -429,31 +530,68 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 		); err != nil {
 			return err
 		}
+		terminated = true
 		return nil
 	}
 
+	r.Header.Set("Webhookx-Event-Id", data.EventID)
+	r.Header.Set("Webhookx-Event-Type", data.EventType)
+	r.Header.Set("Webhookx-Delivery-Id", task.ID)
+
 	iterator := plugins.LoadIterator()
 	c := plugin.NewContext(ctx, r, nil)
+	c.SetHTTPClient(w.httpClient)
 	c.SetRequestBody([]byte(data.Event))
+	c.SetEvent(&plugin.Event{
+		ID:         data.EventID,
+		Type:       data.EventType,
+		Data:       json.RawMessage(data.Event),
+		IngestedAt: time.UnixMilli(data.IngestedAt),
+	})
+	compressor := newCompressor(endpoint)
 	for p := range iterator.Iterate(ctx, plugins.PhaseOutbound, endpoint.ID) {
-		err = p.ExecuteOutbound(c)
-		if err != nil {
-			return fmt.Errorf("failed to execute %s plugin: %v", p.Name(), err)
+		if err := compressor.BeforePlugin(c, p); err != nil {
+			return err
+		}
+		if err := p.ExecuteOutbound(c); err != nil {
+			retrying, err := w.fail(ctx, endpoint, permit, c.Request,
+				fmt.Errorf("failed to execute %s plugin: %v", p.Name(), err), task)
+			terminated = err == nil && !retrying
+			return err
+		}
+		if c.IsSkipped() {
+			w.log.Debugw("delivery is skipped by plugin", "plugin", p.Name(), "task", task.ID)
+			err := w.db.Attempts.UpdateErrorCode(ctx, task.ID,
+				entities.AttemptStatusCanceled,
+				entities.AttemptErrorCodeFiltered)
+			terminated = err == nil
+			return err
 		}
 	}
 
-	c.Request.Header.Set("Webhookx-Event-Id", data.EventID)
-	c.Request.Header.Set("Webhookx-Delivery-Id", task.ID)
+	if err := compressor.AfterPlugins(c); err != nil {
+		return err
+	}
+
+	tlsOptions, err := w.resolveTLS(ctx, endpoint)
+	if err != nil {
+		retrying, err := w.fail(ctx, endpoint, permit, c.Request, err, task)
+		terminated = err == nil && !retrying
+		return err
+	}
 
 	request := &deliverer.Request{
 		Request: c.Request,
 		Body:    c.GetRequestBody(),
+		RawBody: compressor.RawBody(),
 		Timeout: time.Duration(endpoint.Request.Timeout) * time.Millisecond,
+		TLS:     tlsOptions,
+		Config:  endpoint.Config,
 	}
 
 	// deliver the request
 	startAt := time.Now()
-	response := w.deliverer.Send(ctx, request)
+	response := w.send(ctx, iterator, endpoint, c, request)
 	finishAt := time.Now()
 
 	if response.Error != nil {
@@ -461,21 +599,107 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 	}
 	w.log.Debugf("delivery response: %v", response)
 
-	result := buildAttemptResult(request, response)
+	result := newAttemptResult(endpoint, request, response, data.Attempt, startAt, finishAt)
+	w.report(ctx, endpoint, permit, result, response)
+
 	result.ID = task.ID
+	retrying, err := w.complete(ctx, task, endpoint, result, response, finishAt)
+	terminated = err == nil && !retrying
+	return err
+}
+
+// fail completes the tasks with a failed attempt when the request cannot be delivered because of cause
+// (e.g. a plugin failed to obtain a token), so that the retry policy applies as for a failed delivery.
+// It reports whether the next attempts have been scheduled.
+func (w *Worker) fail(ctx context.Context, endpoint *entities.Endpoint, permit circuitbreaker.Permit,
+	r *http.Request, cause error, tasks ...*taskqueue.TaskMessage) (bool, error) {
+	w.log.Infof("failed to delivery request: %v", cause)
+
+	now := time.Now()
+	request := &deliverer.Request{Request: r}
+	response := &deliverer.Response{Request: request, Error: cause}
+	attempt := tasks[0].Data.(*taskqueue.MessageData).Attempt
+	result := newAttemptResult(endpoint, request, response, attempt, now, now)
+	w.report(ctx, endpoint, permit, result, response)
+
+	retrying := false
+	for _, task := range tasks {
+		r := *result
+		r.ID = task.ID
+		scheduled, err := w.complete(ctx, task, endpoint, &r, response, now)
+		if err != nil {
+			return false, err
+		}
+		retrying = retrying || scheduled
+	}
+	return retrying, nil
+}
+
+// send delivers the request, the request is delivered again if an outbound plugin asks to after handling the response
+func (w *Worker) send(ctx context.Context, iterator *plugins.Iterator, endpoint *entities.Endpoint, c *plugin.Context, request *deliverer.Request) *deliverer.Response {
+	d, err := w.deliverers.Get(endpoint.Type)
+	if err != nil {
+		return &deliverer.Response{Request: request, Error: err}
+	}
+	response := d.Send(ctx, request)
+	for p := range iterator.Iterate(ctx, plugins.PhaseOutbound, endpoint.ID) {
+		handler, ok := p.(plugin.OutboundResponseHandler)
+		if !ok {
+			continue
+		}
+		resend, err := handler.HandleOutboundResponse(c, response.StatusCode)
+		if err != nil {
+			w.log.Warnf("failed to handle response by %s plugin: %v", p.Name(), err)
+			continue
+		}
+		if resend {
+			w.log.Debugf("delivering request again as %s plugin asked", p.Name())
+			return d.Send(ctx, request)
+		}
+	}
+	return response
+}
+
+// newAttemptResult builds the result of delivery and decides whether to retry it
+func newAttemptResult(endpoint *entities.Endpoint, request *deliverer.Request, response *deliverer.Response,
+	attempt int, startAt time.Time, finishAt time.Time) *dao.AttemptResult {
+	result := buildAttemptResult(endpoint, request, response)
 	result.AttemptedAt = types.NewTime(startAt)
-	result.Exhausted = data.Attempt >= len(endpoint.Retry.Config.Attempts)
-	if response.ACL.Denied {
+	delay := retry.FromEndpoint(endpoint).NextDelay(attempt + 1)
+	if !result.Exhausted && delay == retry.Stop {
 		result.Exhausted = true
+		result.ExhaustedReason = new(entities.AttemptExhaustedReasonMaxAttempts)
+	}
+	if result.Status == entities.AttemptStatusFailure && !result.Exhausted {
+		result.RetryDecision = newRetryDecision(endpoint, response, delay, finishAt)
 	}
+	return result
+}
 
+// report records the outcome of request into stats, metrics and circuit breaker
+func (w *Worker) report(ctx context.Context, endpoint *entities.Endpoint, permit circuitbreaker.Permit,
+	result *dao.AttemptResult, response *deliverer.Response) {
 	outcome := metrics.Success
 	counter.Add(1)
 	if result.Status == entities.AttemptStatusFailure {
 		failures.Add(1)
 		outcome = metrics.Error
 	}
-	w.cbm.Record(time.Now(), endpoint.ID, outcome)
+	if _, enabled := circuitbreaker.EndpointThresholds(endpoint); enabled {
+		w.cbm.Record(time.Now(), endpoint.ID, outcome)
+	}
+	if permit.IsProbe() {
+		state, err := w.cbm.ReportProbe(ctx, endpoint.ID, outcome == metrics.Success)
+		if err != nil {
+			w.log.Warnf("failed to report probe of circuit breaker %s: %v", endpoint.ID, err)
+		} else if state == circuitbreaker.StateClosed {
+			w.log.Infow("circuit breaker has been closed", "id", endpoint.ID)
+			w.emitSystemEvent(ctx, endpoint, EventTypeEndpointRecovered, &EndpointRecoveredData{
+				Endpoint: newSystemEventEndpoint(endpoint),
+				State:    state,
+			})
+		}
+	}
 
 	if w.services.Metrics.Enabled {
 		w.services.Metrics.AttemptTotalCounter.Add(1)
@@ -484,44 +708,109 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 		}
 		w.services.Metrics.AttemptResponseDurationHistogram.Observe(response.Latancy.Seconds())
 	}
+}
 
-	err = w.db.Attempts.UpdateDelivery(ctx, result)
-	if err != nil {
-		return err
-	}
+// complete stores the result of attempt, then dead-letters or retries the failed attempt.
+// It reports whether the next attempt has been scheduled.
+func (w *Worker) complete(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint,
+	result *dao.AttemptResult, response *deliverer.Response, finishAt time.Time) (bool, error) {
+	data := task.Data.(*taskqueue.MessageData)
 
-	ad := newAttemptDetail(task.ID, endpoint.WorkspaceId, response)
-	w.queueRequestLog.Add(ctx, ad)
+	var deadLetter *entities.DeadLetter
+	var nextAttempt *entities.Attempt
+	if result.Status != entities.AttemptStatusSuccess {
+		if result.Exhausted {
+			w.log.Debugf("webhook delivery exhausted : %s", task.ID)
+			var err error
+			deadLetter, err = w.newDeadLetter(ctx, task, endpoint, result)
+			if err != nil {
+				return false, err
+			}
+		} else {
+			nextAttempt = &entities.Attempt{
+				ID:            utils.KSUID(),
+				EventId:       data.EventID,
+				EndpointId:    endpoint.ID,
+				Status:        entities.AttemptStatusInit,
+				AttemptNumber: data.Attempt + 1,
+				ScheduledAt:   types.NewTime(finishAt.Add(time.Duration(result.RetryDecision.Delay) * time.Millisecond)),
+				TriggerMode:   entities.AttemptTriggerModeAutomatic,
+				Event: &entities.Event{
+					ID:         data.EventID,
+					EventType:  data.EventType,
+					Data:       json.RawMessage(data.Event),
+					IngestedAt: types.NewTime(time.UnixMilli(data.IngestedAt)),
+				},
+			}
+			nextAttempt.WorkspaceId = endpoint.WorkspaceId
+		}
+	}
 
-	if result.Status == entities.AttemptStatusSuccess {
+	// the result is stored along with the dead letter or the next attempt, otherwise the task
+	// would be handled again and the webhook delivered twice
+	err := w.db.TX(ctx, func(ctx context.Context) error {
+		if err := w.db.Attempts.UpdateDelivery(ctx, result); err != nil {
+			return err
+		}
+		if deadLetter != nil {
+			return w.db.DeadLetters.Insert(ctx, deadLetter)
+		}
+		if nextAttempt != nil {
+			return w.db.Attempts.Insert(ctx, nextAttempt)
+		}
 		return nil
+	})
+	if err != nil {
+		return false, err
 	}
 
-	if result.Exhausted {
-		w.log.Debugf("webhook delivery exhausted : %s", task.ID)
-		return nil
+	if ad := newAttemptDetail(task.ID, endpoint, result, response); ad != nil {
+		w.queueRequestLog.Add(ctx, ad)
 	}
 
-	delay := endpoint.Retry.Config.Attempts[data.Attempt]
-	nextAttempt := &entities.Attempt{
-		ID:            utils.KSUID(),
-		EventId:       data.EventID,
-		EndpointId:    endpoint.ID,
-		Status:        entities.AttemptStatusInit,
-		AttemptNumber: data.Attempt + 1,
-		ScheduledAt:   types.NewTime(finishAt.Add(time.Second * time.Duration(delay))),
-		TriggerMode:   entities.AttemptTriggerModeAutomatic,
-		Event:         &entities.Event{ID: data.EventID, Data: json.RawMessage(data.Event)},
+	if deadLetter != nil {
+		w.emitSystemEvent(ctx, endpoint, EventTypeAttemptExhausted, &AttemptExhaustedData{
+			Endpoint:      newSystemEventEndpoint(endpoint),
+			AttemptId:     task.ID,
+			AttemptNumber: data.Attempt,
+			EventId:       deadLetter.EventId,
+			EventType:     deadLetter.EventType,
+			Reason:        deadLetter.Reason,
+			ErrorCode:     deadLetter.ErrorCode,
+			DeadLetterId:  deadLetter.ID,
+		})
 	}
-	nextAttempt.WorkspaceId = endpoint.WorkspaceId
 
-	err = w.db.Attempts.Insert(ctx, nextAttempt)
+	if nextAttempt != nil {
+		w.services.Task.ScheduleAttempts(ctx, []*entities.Attempt{nextAttempt})
+		return true, nil
+	}
+	return false, nil
+}
+
+// newDeadLetter returns the dead letter of exhausted delivery, it returns nil if the event no longer exists
+func (w *Worker) newDeadLetter(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint, result *dao.AttemptResult) (*entities.DeadLetter, error) {
+	data := task.Data.(*taskqueue.MessageData)
+	event, err := w.db.Events.Get(ctx, data.EventID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if event == nil {
+		w.log.Warnf("event %s not found, skip dead-lettering attempt %s", data.EventID, task.ID)
+		return nil, nil
 	}
 
-	w.services.Task.ScheduleAttempts(ctx, []*entities.Attempt{nextAttempt})
-	return nil
+	deadLetter := &entities.DeadLetter{
+		ID:         utils.KSUID(),
+		EventId:    data.EventID,
+		EventType:  event.EventType,
+		EndpointId: endpoint.ID,
+		AttemptId:  task.ID,
+		Reason:     *result.ExhaustedReason,
+		ErrorCode:  result.ErrorCode,
+	}
+	deadLetter.WorkspaceId = endpoint.WorkspaceId
+	return deadLetter, nil
 }
 
 func (w *Worker) validateEndpoint(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint) error {
@@ -546,25 +835,108 @@ func (w *Worker) validateEndpoint(ctx context.Context, task *taskqueue.TaskMessa
 		}
 		return ErrTerminated
 	}
-	if endpoint.RateLimit != nil {
-		d := time.Duration(endpoint.RateLimit.Period) * time.Second
-		res, err := w.services.RateLimiter.Allow(ctx, endpoint.ID, endpoint.RateLimit.Quota, d)
-		if err != nil {
+	return nil
+}
+
+// allowRateLimit charges one delivery request against the rate limit of endpoint,
+// it returns the time to retry if the rate limit is exceeded
+func (w *Worker) allowRateLimit(ctx context.Context, endpoint *entities.Endpoint) (bool, time.Time, error) {
+	if endpoint.RateLimit == nil {
+		return true, time.Time{}, nil
+	}
+	d := time.Duration(endpoint.RateLimit.Period) * time.Second
+	res, err := w.services.RateLimiter.Allow(ctx, endpoint.ID, endpoint.RateLimit.Quota, d)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	return res.Allowed, time.Now().Add(d), nil
+}
+
+// acquireRateLimit holds back the task until the next period if the rate limit is exceeded
+func (w *Worker) acquireRateLimit(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint) error {
+	allowed, next, err := w.allowRateLimit(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		task.ScheduledAt = next
+		w.log.Debugw("rate limit exceeded", "endpoint", endpoint.ID, "task", task.ID, "next", task.ScheduledAt)
+		if err := w.services.Task.ScheduleTask(ctx, task.ID, task.ScheduledAt); err != nil {
 			return err
 		}
-		if !res.Allowed {
-			task.ScheduledAt = time.Now().Add(d)
-			w.log.Debugw("rate limit exceeded", "endpoint", endpoint.ID, "task", task.ID, "next", task.ScheduledAt)
-			err := w.services.Task.ScheduleTask(ctx, task.ID, task.ScheduledAt)
-			if err != nil {
-				return err
-			}
-			return ErrRateLimitExceeded
+		return ErrRateLimitExceeded
+	}
+	return nil
+}
+
+// removeOrdering removes the event from the sequences of ordered endpoint when the attempt is dropped
+// before it is handled, otherwise the later events would wait until the lease of head expires.
+func (w *Worker) removeOrdering(ctx context.Context, endpointId string, eventId string) {
+	endpoint, err := mcache.Load(ctx, constants.EndpointCacheKey.Build(endpointId), nil, w.db.Endpoints.Get, endpointId)
+	if err != nil || endpoint == nil || endpoint.Ordering == nil {
+		return
+	}
+	if err := w.sequencer.Remove(ctx, endpoint, eventId); err != nil {
+		w.log.Warnf("failed to remove ordering of event %s: %v", eventId, err)
+	}
+}
+
+// acquireOrdering holds back the task until the earlier events of the same ordering key are delivered
+func (w *Worker) acquireOrdering(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint, key string) error {
+	data := task.Data.(*taskqueue.MessageData)
+	ok, err := w.sequencer.Acquire(ctx, endpoint, key, data.EventID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		task.ScheduledAt = time.Now().Add(orderingBlockedWait)
+		w.log.Debugw("blocked by earlier event", "endpoint", endpoint.ID, "key", key, "task", task.ID, "next", task.ScheduledAt)
+		if err := w.services.Task.ScheduleTask(ctx, task.ID, task.ScheduledAt); err != nil {
+			return err
 		}
+		return ErrOrderingBlocked
 	}
 	return nil
 }
 
+// acquireConcurrency holds back the task while the in-flight requests of endpoint reached max_concurrency
+func (w *Worker) acquireConcurrency(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint) error {
+	lease := time.Duration(endpoint.Request.Timeout)*time.Millisecond + concurrencyLeaseMargin
+	ok, err := w.services.Semaphore.Acquire(ctx, endpoint.ID, task.ID, *endpoint.MaxConcurrency, lease)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		task.ScheduledAt = time.Now().Add(concurrencyLimitedWait)
+		w.log.Debugw("concurrency limit exceeded", "endpoint", endpoint.ID, "task", task.ID, "next", task.ScheduledAt)
+		if err := w.services.Task.ScheduleTask(ctx, task.ID, task.ScheduledAt); err != nil {
+			return err
+		}
+		return ErrConcurrencyLimited
+	}
+	return nil
+}
+
+// acquireCircuitBreaker holds back the task while the circuit breaker of endpoint is open
+func (w *Worker) acquireCircuitBreaker(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint) (circuitbreaker.Permit, error) {
+	if _, enabled := circuitbreaker.EndpointThresholds(endpoint); !enabled {
+		return circuitbreaker.Permit{State: circuitbreaker.StateClosed, Allowed: true}, nil
+	}
+	permit, err := w.cbm.Acquire(ctx, endpoint.ID)
+	if err != nil {
+		return permit, err
+	}
+	if !permit.Allowed {
+		task.ScheduledAt = permit.RetryAt
+		w.log.Debugw("circuit breaker is open", "endpoint", endpoint.ID, "state", permit.State, "task", task.ID, "next", task.ScheduledAt)
+		if err := w.services.Task.ScheduleTask(ctx, task.ID, task.ScheduledAt); err != nil {
+			return permit, err
+		}
+		return permit, ErrCircuitBreakerOpen
+	}
+	return permit, nil
+}
+
 func newRequestFromEndpoint(endpoint *entities.Endpoint) (*http.Request, error) {
 	r, err := http.NewRequest(endpoint.Request.Method, endpoint.Request.URL, nil)
 	if err != nil {
@@ -579,7 +951,52 @@ func newRequestFromEndpoint(endpoint *entities.Endpoint) (*http.Request, error)
 	return r, nil
 }
 
-func buildAttemptResult(request *deliverer.Request, response *deliverer.Response) *dao.AttemptResult {
+// resolveTLS returns the TLS options of endpoint, the secret references are resolved by secret manager
+func (w *Worker) resolveTLS(ctx context.Context, endpoint *entities.Endpoint) (*deliverer.TLSOptions, error) {
+	config := endpoint.Request.TLS
+	if config == nil {
+		return nil, nil
+	}
+
+	resolve := func(name string, value *string) (string, error) {
+		if value == nil {
+			return "", nil
+		}
+		if !reference.IsReference(*value) {
+			return *value, nil
+		}
+		if w.opts.SecretManager == nil {
+			return "", fmt.Errorf("request.tls.%s is a secret reference but secret manager is not enabled", name)
+		}
+		ref, err := reference.Parse(*value)
+		if err != nil {
+			return "", fmt.Errorf("request.tls.%s parse error: %w", name, err)
+		}
+		resolved, err := w.opts.SecretManager.ResolveReference(ctx, ref)
+		if err != nil {
+			return "", fmt.Errorf("request.tls.%s resolve error: %w", name, err)
+		}
+		return resolved, nil
+	}
+
+	opts := &deliverer.TLSOptions{InsecureSkipVerify: config.InsecureSkipVerify}
+	var err error
+	if opts.ClientCert, err = resolve("client_cert", config.ClientCert); err != nil {
+		return nil, err
+	}
+	if opts.ClientKey, err = resolve("client_key", config.ClientKey); err != nil {
+		return nil, err
+	}
+	if opts.CACert, err = resolve("ca_cert", config.CACert); err != nil {
+		return nil, err
+	}
+	if config.ServerName != nil {
+		opts.ServerName = *config.ServerName
+	}
+	return opts, nil
+}
+
+func buildAttemptResult(endpoint *entities.Endpoint, request *deliverer.Request, response *deliverer.Response) *dao.AttemptResult {
 	result := &dao.AttemptResult{
 		Request: &entities.AttemptRequest{
 			URL:    request.Request.URL.String(),
@@ -593,33 +1010,114 @@ func buildAttemptResult(request *deliverer.Request, response *deliverer.Response
 			result.ErrorCode = new(entities.AttemptErrorCodeTimeout)
 		} else if response.ACL.Denied {
 			result.ErrorCode = new(entities.AttemptErrorCodeDenied)
+		} else if response.ErrorCode != "" {
+			result.ErrorCode = new(response.ErrorCode)
 		} else {
 			result.ErrorCode = new(entities.AttemptErrorCodeUnknown)
 		}
 	}
 
-	if !response.Is2xx() {
+	if !response.Succeeded() {
 		result.Status = entities.AttemptStatusFailure
 	}
 
 	if response.StatusCode != 0 {
 		result.Response = &entities.AttemptResponse{
-			Status:  response.StatusCode,
-			Latency: response.Latancy.Milliseconds(),
+			Status:        response.StatusCode,
+			Latency:       response.Latancy.Milliseconds(),
+			BodyTruncated: response.ResponseBodyTruncated,
+		}
+	}
+
+	if response.ACL.Denied {
+		result.Exhausted = true
+		result.ExhaustedReason = new(entities.AttemptExhaustedReasonDenied)
+	} else if result.Status == entities.AttemptStatusFailure {
+		if reason := nonRetryableReason(endpoint.Retry.Policy, result); reason != "" {
+			result.Exhausted = true
+			result.ExhaustedReason = &reason
 		}
 	}
 
 	return result
 }
 
-func newAttemptDetail(id string, wid string, response *deliverer.Response) *entities.AttemptDetail {
+// nonRetryableReason returns the reason why the failed attempt is not retryable according to
+// the retry policy, or an empty string if it is retryable.
+func nonRetryableReason(policy *entities.RetryPolicy, result *dao.AttemptResult) entities.AttemptExhaustedReason {
+	if policy == nil {
+		return ""
+	}
+	if result.ErrorCode != nil {
+		if !policy.IsRetryableError(*result.ErrorCode) {
+			return entities.AttemptExhaustedReasonNonRetryableError
+		}
+		return ""
+	}
+	if result.Response != nil && !policy.IsRetryableStatus(result.Response.Status) {
+		return entities.AttemptExhaustedReasonNonRetryableStatus
+	}
+	return ""
+}
+
+// newRetryDecision decides the delay of next attempt, the Retry-After header of 429 and 503 responses
+// overrides the delay computed by retry strategy when endpoint opts in.
+func newRetryDecision(endpoint *entities.Endpoint, response *deliverer.Response, delay time.Duration, now time.Time) *entities.AttemptRetryDecision {
+	decision := &entities.AttemptRetryDecision{
+		Reason: entities.AttemptRetryReasonStrategy,
+		Delay:  delay.Milliseconds(),
+	}
+
+	if endpoint.Retry.RetryAfter == nil {
+		return decision
+	}
+	if response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusServiceUnavailable {
+		return decision
+	}
+
+	value := response.Header.Get("Retry-After")
+	if d, ok := utils.ParseRetryAfter(value, now); ok {
+		maxDelay := time.Duration(endpoint.Retry.RetryAfter.MaxDelay) * time.Second
+		decision.Reason = entities.AttemptRetryReasonRetryAfter
+		decision.Delay = min(d, maxDelay).Milliseconds()
+		decision.RetryAfter = &value
+	}
+
+	return decision
+}
+
+// newAttemptDetail returns the attempt detail captured according to the endpoint's capture,
+// or nil if nothing is captured.
+func newAttemptDetail(id string, endpoint *entities.Endpoint, result *dao.AttemptResult, response *deliverer.Response) *entities.AttemptDetail {
+	mode := entities.CaptureModeFull
+	var redactHeaders []string
+	if endpoint.Capture != nil {
+		mode = endpoint.Capture.Mode
+		redactHeaders = endpoint.Capture.RedactHeaders
+	}
+	switch mode {
+	case entities.CaptureModeNone:
+		return nil
+	case entities.CaptureModeOnFailure:
+		if result.Status == entities.AttemptStatusSuccess {
+			return nil
+		}
+	}
+
 	ad := &entities.AttemptDetail{}
 	ad.ID = id
-	ad.WorkspaceId = wid
-	ad.RequestHeaders = utils.HeaderMap(response.Request.Request.Header)
-	ad.RequestBody = new(string(response.Request.Body))
+	ad.WorkspaceId = endpoint.WorkspaceId
+	ad.RequestHeaders = redactHeaderMap(utils.HeaderMap(response.Request.Request.Header), redactHeaders)
 	if len(response.Header) > 0 {
-		ad.ResponseHeaders = new(entities.Headers(utils.HeaderMap(response.Header)))
+		ad.ResponseHeaders = new(entities.Headers(redactHeaderMap(utils.HeaderMap(response.Header), redactHeaders)))
+	}
+	if mode == entities.CaptureModeHeadersOnly {
+		return ad
+	}
+	if response.Request.RawBody != nil {
+		ad.RequestBody = new(string(response.Request.RawBody))
+	} else {
+		ad.RequestBody = new(string(response.Request.Body))
 	}
 	if response.ResponseBody != nil {
 		ad.ResponseBody = new(string(response.ResponseBody))
@@ -627,6 +1125,19 @@ func newAttemptDetail(id string, wid string, response *deliverer.Response) *enti
 	return ad
 }
 
+// redactHeaderMap replaces the values of headers in names (case-insensitive)
+func redactHeaderMap(headers map[string]string, names []string) map[string]string {
+	for name := range headers {
+		for _, redact := range names {
+			if strings.EqualFold(name, redact) {
+				headers[name] = RedactedValue
+				break
+			}
+		}
+	}
+	return headers
+}
+
 func (w *Worker) detectEnabledEndpoints(ctx context.Context) error {
 	var q dao.EndpointQuery
 	q.Enabled = new(true)
@@ -634,26 +1145,32 @@ func (w *Worker) detectEnabledEndpoints(ctx context.Context) error {
 	it := w.db.Endpoints.Iterate(ctx, q.ToQuery())
 	for it.Next() {
 		endpoint := it.Current()
-		cb, err := w.cbm.GetCircuitBreaker(ctx, endpoint.ID)
+		thresholds, enabled := circuitbreaker.EndpointThresholds(endpoint)
+		if !enabled {
+			continue
+		}
+		cb, opened, err := w.cbm.Evaluate(ctx, endpoint.ID, thresholds)
 		if err != nil {
-			return fmt.Errorf("failed to query endpoint's circuit breaker %s: %v", endpoint.ID, err)
+			return fmt.Errorf("failed to evaluate endpoint's circuit breaker %s: %v", endpoint.ID, err)
 		}
 		// verbose
 		// w.log.Debugw("circuit breaker",
 		//	"id", endpoint.ID,
 		//	"metrics", cb.Metric(),
 		//	"failure_rate", fmt.Sprintf("%.2f%%", cb.Metric().FailureRate()*100))
-		if cb.State() == circuitbreaker.StateOpen {
-			changed, err := w.db.Endpoints.Disable(ctx, endpoint.ID)
-			if err != nil {
-				return fmt.Errorf("failed to disable endpoint %s: %v", endpoint.ID, err)
-			}
-			if changed {
-				w.log.Warnw("endpoint has been disabled",
-					"id", endpoint.ID,
-					"metrics", cb.Metric(),
-					"failure_rate", fmt.Sprintf("%.2f%%", cb.Metric().FailureRate()*100))
-			}
+		if opened {
+			w.log.Warnw("circuit breaker has been opened, delivery is paused",
+				"id", endpoint.ID,
+				"metrics", cb.Metric(),
+				"failure_rate", fmt.Sprintf("%.2f%%", cb.Metric().FailureRate()*100))
+			w.emitSystemEvent(ctx, endpoint, EventTypeEndpointDisabled, &EndpointDisabledData{
+				Endpoint:    newSystemEventEndpoint(endpoint),
+				State:       cb.State(),
+				Reason:      cb.Reason(),
+				FailureRate: cb.Metric().FailureRate(),
+				Metric:      cb.Metric(),
+				OpenedAt:    types.NewTime(cb.OpenedAt()),
+			})
 		}
 	}
 	if err := it.Err(); err != nil {
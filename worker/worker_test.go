@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/worker/deliverer"
+)
+
+func TestNewAttemptDetail(t *testing.T) {
+	newResponse := func() *deliverer.Response {
+		r, _ := http.NewRequest("POST", "https://example.com", nil)
+		r.Header.Set("Authorization", "Bearer token")
+		r.Header.Set("Content-Type", "application/json")
+		return &deliverer.Response{
+			Request:      &deliverer.Request{Request: r, Body: []byte(`{"foo":"bar"}`)},
+			StatusCode:   200,
+			Header:       http.Header{"Set-Cookie": []string{"session=1"}},
+			ResponseBody: []byte("OK"),
+		}
+	}
+	success := &dao.AttemptResult{Status: entities.AttemptStatusSuccess}
+	failure := &dao.AttemptResult{Status: entities.AttemptStatusFailure}
+
+	t.Run("should capture in full by default", func(t *testing.T) {
+		endpoint := &entities.Endpoint{}
+		endpoint.WorkspaceId = "ws"
+		ad := newAttemptDetail("a1", endpoint, success, newResponse())
+		assert.Equal(t, "a1", ad.ID)
+		assert.Equal(t, "ws", ad.WorkspaceId)
+		assert.EqualValues(t, map[string]string{"Authorization": "Bearer token", "Content-Type": "application/json"}, ad.RequestHeaders)
+		assert.Equal(t, `{"foo":"bar"}`, *ad.RequestBody)
+		assert.EqualValues(t, map[string]string{"Set-Cookie": "session=1"}, *ad.ResponseHeaders)
+		assert.Equal(t, "OK", *ad.ResponseBody)
+	})
+
+	t.Run("should capture headers only with redaction", func(t *testing.T) {
+		endpoint := &entities.Endpoint{Capture: &entities.Capture{
+			Mode:          entities.CaptureModeHeadersOnly,
+			RedactHeaders: []string{"authorization", "SET-COOKIE"},
+		}}
+		ad := newAttemptDetail("a1", endpoint, success, newResponse())
+		assert.EqualValues(t, map[string]string{"Authorization": RedactedValue, "Content-Type": "application/json"}, ad.RequestHeaders)
+		assert.EqualValues(t, map[string]string{"Set-Cookie": RedactedValue}, *ad.ResponseHeaders)
+		assert.Nil(t, ad.RequestBody)
+		assert.Nil(t, ad.ResponseBody)
+	})
+
+	t.Run("should capture the body before compression", func(t *testing.T) {
+		response := newResponse()
+		response.Request.RawBody = response.Request.Body
+		response.Request.Body = []byte("compressed")
+		ad := newAttemptDetail("a1", &entities.Endpoint{}, success, response)
+		assert.Equal(t, `{"foo":"bar"}`, *ad.RequestBody)
+	})
+
+	t.Run("should capture nothing", func(t *testing.T) {
+		endpoint := &entities.Endpoint{Capture: &entities.Capture{Mode: entities.CaptureModeNone}}
+		assert.Nil(t, newAttemptDetail("a1", endpoint, failure, newResponse()))
+	})
+
+	t.Run("should capture failed attempts only", func(t *testing.T) {
+		endpoint := &entities.Endpoint{Capture: &entities.Capture{Mode: entities.CaptureModeOnFailure}}
+		assert.Nil(t, newAttemptDetail("a1", endpoint, success, newResponse()))
+		ad := newAttemptDetail("a1", endpoint, failure, newResponse())
+		assert.Equal(t, `{"foo":"bar"}`, *ad.RequestBody)
+		assert.Equal(t, "OK", *ad.ResponseBody)
+	})
+}